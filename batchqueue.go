@@ -9,6 +9,7 @@ type batchQueue struct {
 	pending    []string
 	maxWorkers int
 	active     int
+	fetchOnly  bool // fetch --all --prune instead of a status refresh
 }
 
 func newBatchQueue(paths []string, maxWorkers int) batchQueue {
@@ -49,3 +50,27 @@ func (q *batchQueue) Next() (string, bool) {
 func (q *batchQueue) Done() bool {
 	return len(q.pending) == 0 && q.active == 0
 }
+
+// fetchLimiter bounds how many status/fetch git subprocesses run at once,
+// across all commands (batch refreshes, single refreshes, branch loads).
+var fetchLimiter = newLimiter(4)
+
+// limiter is a counting semaphore backed by a buffered channel.
+type limiter chan struct{}
+
+func newLimiter(n int) limiter {
+	if n < 1 {
+		n = 1
+	}
+	return make(limiter, n)
+}
+
+// Acquire blocks until a slot is free.
+func (l limiter) Acquire() {
+	l <- struct{}{}
+}
+
+// Release frees a slot taken by Acquire.
+func (l limiter) Release() {
+	<-l
+}
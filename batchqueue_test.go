@@ -139,3 +139,34 @@ func TestEmptyQueue(t *testing.T) {
 		t.Error("empty queue should be done immediately")
 	}
 }
+
+func TestLimiterBoundsConcurrency(t *testing.T) {
+	l := newLimiter(2)
+	l.Acquire()
+	l.Acquire()
+
+	acquired := make(chan struct{})
+	go func() {
+		l.Acquire()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("third acquire should block while limit is reached")
+	default:
+	}
+
+	l.Release()
+	<-acquired
+	if len(l) != 2 {
+		t.Errorf("expected 2 slots in use, got %d", len(l))
+	}
+}
+
+func TestNewLimiterMinimumOne(t *testing.T) {
+	l := newLimiter(0)
+	if cap(l) != 1 {
+		t.Errorf("expected capacity 1, got %d", cap(l))
+	}
+}
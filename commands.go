@@ -1,31 +1,70 @@
 package main
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io/fs"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// isHiddenScanSkip reports whether the scanner should skip a directory for
+// being hidden. ".git" itself is never a repo candidate.
+func isHiddenScanSkip(name string, scanHidden bool) bool {
+	if name == ".git" {
+		return true
+	}
+	return !scanHidden && strings.HasPrefix(name, ".")
+}
+
 func scanForRepos(gitDir string) tea.Cmd {
 	return func() tea.Msg {
 		var repos []Repo
+		config := loadConfig()
+		excludes := loadExcludePatterns(gitDir, config.ExcludePatterns)
+
+		info, err := os.Stat(gitDir)
+		if err != nil {
+			return repoFoundMsg{err: err}
+		}
+		if !info.IsDir() {
+			return repoFoundMsg{err: fmt.Errorf("%s is not a directory", gitDir)}
+		}
 
-		filepath.WalkDir(gitDir, func(path string, d os.DirEntry, err error) error {
+		unreadable := 0
+		walkErr := filepath.WalkDir(gitDir, func(path string, d os.DirEntry, err error) error {
 			if err != nil {
+				if path == gitDir {
+					return err // can't list the git directory itself
+				}
+				unreadable++
 				return nil // Skip directories we can't read
 			}
 
-			// Skip hidden directories (except the root)
-			if d.IsDir() && strings.HasPrefix(d.Name(), ".") && path != gitDir {
+			// Skip hidden directories (except the root) unless configured to
+			// scan them. Exclude patterns still apply to those that are scanned.
+			if d.IsDir() && path != gitDir && isHiddenScanSkip(d.Name(), config.ScanHidden) {
 				return filepath.SkipDir
 			}
 
+			// Skip directories matching an exclude pattern
+			if d.IsDir() && path != gitDir {
+				if relPath, err := filepath.Rel(gitDir, path); err == nil && isExcluded(relPath, excludes) {
+					return filepath.SkipDir
+				}
+			}
+
 			// Check if this directory contains a .git folder
 			if d.IsDir() {
 				gitPath := filepath.Join(path, ".git")
@@ -33,9 +72,10 @@ func scanForRepos(gitDir string) tea.Cmd {
 					// Calculate relative name from gitDir
 					relPath, _ := filepath.Rel(gitDir, path)
 					repos = append(repos, Repo{
-						Path:   path,
-						Name:   relPath,
-						Status: StatusUnknown,
+						Path:      path,
+						Name:      relPath,
+						Status:    StatusUnknown,
+						RemoteURL: originURL(path),
 					})
 					// Don't descend into git repos (no nested repos)
 					return filepath.SkipDir
@@ -44,13 +84,56 @@ func scanForRepos(gitDir string) tea.Cmd {
 
 			return nil
 		})
+		if walkErr != nil {
+			return repoFoundMsg{err: walkErr}
+		}
+
+		// Pinned repos are listed even when they live outside gitDir
+		found := make(map[string]bool, len(repos))
+		for _, r := range repos {
+			found[r.Path] = true
+		}
+		var invalidPinned []string
+		for _, path := range config.GetPinnedRepos() {
+			if found[path] {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+				invalidPinned = append(invalidPinned, path)
+				continue
+			}
+			found[path] = true
+			repos = append(repos, Repo{
+				Path:      path,
+				Name:      pinnedRepoName(gitDir, path),
+				Status:    StatusUnknown,
+				RemoteURL: originURL(path),
+			})
+		}
+
+		return repoFoundMsg{repos: repos, invalidPinned: invalidPinned, unreadable: unreadable}
+	}
+}
 
-		return repoFoundMsg{repos: repos}
+// pinnedRepoName names a pinned repo: relative to gitDir when inside it,
+// otherwise its path with the home directory shortened to "~"
+func pinnedRepoName(gitDir, path string) string {
+	if rel, err := filepath.Rel(gitDir, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return rel
 	}
+	if home, err := os.UserHomeDir(); err == nil {
+		if rel, err := filepath.Rel(home, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.Join("~", rel)
+		}
+	}
+	return path
 }
 
 func checkGitStatus(path string) tea.Cmd {
 	return func() tea.Msg {
+		fetchLimiter.Acquire()
+		defer fetchLimiter.Release()
+
 		// Get branch name
 		branchCmd := exec.Command("git", "-C", path, "rev-parse", "--abbrev-ref", "HEAD")
 		branchOut, _ := branchCmd.Output()
@@ -58,76 +141,273 @@ func checkGitStatus(path string) tea.Cmd {
 		if branch == "" {
 			branch = "?"
 		}
+		// rev-parse prints "HEAD" when no branch is checked out
+		detached := branch == "HEAD"
+
+		// A fresh `git init` or empty clone has no commits: HEAD points at a
+		// branch that doesn't exist yet, so name it from symbolic-ref instead
+		empty := exec.Command("git", "-C", path, "rev-parse", "--verify", "--quiet", "HEAD").Run() != nil
+		if empty {
+			if refOut, err := exec.Command("git", "-C", path, "symbolic-ref", "--short", "HEAD").Output(); err == nil {
+				branch = strings.TrimSpace(string(refOut))
+				detached = false
+			}
+		}
 
-		// Fetch from remote (silent, don't block on network issues)
-		fetchCmd := exec.Command("git", "-C", path, "fetch", "--quiet")
-		fetchCmd.Run() // ignore errors
-
-		// Check how many commits behind remote
+		// Check how many commits behind remote (against already-fetched refs)
 		behindCount := 0
+		hasUpstream := false
 		behindCmd := exec.Command("git", "-C", path, "rev-list", "--count", "HEAD..@{u}")
 		behindOut, err := behindCmd.Output()
 		if err == nil {
+			hasUpstream = true
 			if count, parseErr := strconv.Atoi(strings.TrimSpace(string(behindOut))); parseErr == nil {
 				behindCount = count
 			}
 		}
 
+		// Age of the newest upstream commit, to convey how urgent pulling is
+		upstreamAge := ""
+		if behindCount > 0 {
+			ageCmd := exec.Command("git", "-C", path, "log", "-1", "--format=%cr", "@{u}")
+			if ageOut, err := ageCmd.Output(); err == nil {
+				upstreamAge = strings.TrimSpace(string(ageOut))
+			}
+		}
+
+		// Time and subject of the last commit, for sorting by recency and spotting
+		// stale repos. Fails on repos without commits, leaving them empty.
+		var lastCommitTime int64
+		lastCommit, lastSubject := "", ""
+		lastCmd := exec.Command("git", "-C", path, "log", "-1", "--format=%ct|%cr|%s")
+		if lastOut, err := lastCmd.Output(); err == nil {
+			if parts := strings.SplitN(strings.TrimSpace(string(lastOut)), "|", 3); len(parts) == 3 {
+				lastCommitTime, _ = strconv.ParseInt(parts[0], 10, 64)
+				lastCommit = parts[1]
+				lastSubject = parts[2]
+			}
+		}
+
+		// Nearest tag reachable from HEAD and how many commits HEAD is past it.
+		// Fails on repos without tags, leaving both empty.
+		latestTag, tagDistance := "", 0
+		if descOut, err := exec.Command("git", "-C", path, "describe", "--tags", "--long").Output(); err == nil {
+			latestTag, tagDistance = parseDescribe(strings.TrimSpace(string(descOut)))
+		}
+
+		// A merge or rebase stopped half-way (usually on conflicts)
+		operation := inProgressOp(path)
+
+		// Count stash entries
+		stashCount := 0
+		stashCmd := exec.Command("git", "-C", path, "stash", "list")
+		stashOut, err := stashCmd.Output()
+		if err == nil {
+			if stashes := strings.TrimSpace(string(stashOut)); stashes != "" {
+				stashCount = len(strings.Split(stashes, "\n"))
+			}
+		}
+
 		// Get local status
 		cmd := exec.Command("git", "-C", path, "status", "--porcelain")
 		output, err := cmd.Output()
 
 		if err != nil {
+			return statusUpdatedMsg{
+				path:           path,
+				branch:         branch,
+				detached:       detached,
+				status:         StatusError,
+				text:           "failed to get status",
+				errDetail:      gitErrorText(err),
+				behindCount:    0,
+				stashCount:     stashCount,
+				hasUpstream:    hasUpstream,
+				lastCommitTime: lastCommitTime,
+				lastCommit:     lastCommit,
+				lastSubject:    lastSubject,
+				operation:      operation,
+				latestTag:      latestTag,
+				tagDistance:    tagDistance,
+			}
+		}
+
+		lines := strings.TrimSpace(string(output))
+		if empty {
+			files := parseChangedFiles(strings.TrimRight(string(output), "\n"))
+			text := ""
+			if len(files) > 0 {
+				text = changeSummary(files)
+			}
 			return statusUpdatedMsg{
 				path:        path,
 				branch:      branch,
-				status:      StatusError,
-				text:        "failed to get status",
-				behindCount: 0,
+				status:      StatusEmpty,
+				text:        text,
+				changed:     len(files),
+				stashCount:  stashCount,
+				hasUpstream: hasUpstream,
 			}
 		}
 
-		lines := strings.TrimSpace(string(output))
 		if lines == "" {
 			// Clean locally
 			if behindCount > 0 {
 				return statusUpdatedMsg{
-					path:        path,
-					branch:      branch,
-					status:      StatusCleanBehind,
-					text:        "",
-					behindCount: behindCount,
+					path:           path,
+					branch:         branch,
+					detached:       detached,
+					status:         StatusCleanBehind,
+					text:           "",
+					behindCount:    behindCount,
+					stashCount:     stashCount,
+					hasUpstream:    hasUpstream,
+					upstreamAge:    upstreamAge,
+					lastCommitTime: lastCommitTime,
+					lastCommit:     lastCommit,
+					lastSubject:    lastSubject,
+					operation:      operation,
+					latestTag:      latestTag,
+					tagDistance:    tagDistance,
 				}
 			}
 			return statusUpdatedMsg{
-				path:        path,
-				branch:      branch,
-				status:      StatusClean,
-				text:        "",
-				behindCount: 0,
+				path:           path,
+				branch:         branch,
+				detached:       detached,
+				status:         StatusClean,
+				text:           "",
+				behindCount:    0,
+				stashCount:     stashCount,
+				hasUpstream:    hasUpstream,
+				lastCommitTime: lastCommitTime,
+				lastCommit:     lastCommit,
+				lastSubject:    lastSubject,
+				operation:      operation,
+				latestTag:      latestTag,
+				tagDistance:    tagDistance,
+			}
+		}
+
+		if conflicts := countConflicts(lines); conflicts > 0 {
+			return statusUpdatedMsg{
+				path:           path,
+				branch:         branch,
+				detached:       detached,
+				status:         StatusConflict,
+				text:           fmt.Sprintf("%d conflicts", conflicts),
+				behindCount:    behindCount,
+				stashCount:     stashCount,
+				hasUpstream:    hasUpstream,
+				upstreamAge:    upstreamAge,
+				lastCommitTime: lastCommitTime,
+				lastCommit:     lastCommit,
+				lastSubject:    lastSubject,
+				operation:      operation,
+				latestTag:      latestTag,
+				tagDistance:    tagDistance,
 			}
 		}
 
-		lineCount := len(strings.Split(lines, "\n"))
+		// Parse the untrimmed output, trimming would eat the first line's index column
+		files := parseChangedFiles(strings.TrimRight(string(output), "\n"))
 		return statusUpdatedMsg{
+			path:           path,
+			branch:         branch,
+			detached:       detached,
+			status:         StatusDirty,
+			text:           changeSummary(files),
+			changed:        len(files),
+			behindCount:    behindCount,
+			stashCount:     stashCount,
+			hasUpstream:    hasUpstream,
+			upstreamAge:    upstreamAge,
+			lastCommitTime: lastCommitTime,
+			lastCommit:     lastCommit,
+			lastSubject:    lastSubject,
+			operation:      operation,
+			latestTag:      latestTag,
+			tagDistance:    tagDistance,
+		}
+	}
+}
+
+// fetchRepo fetches from the remote without touching the working tree.
+// The status check is dispatched separately once the fetch completes.
+func fetchRepo(path string) tea.Cmd {
+	return func() tea.Msg {
+		fetchLimiter.Acquire()
+		defer fetchLimiter.Release()
+
+		// Fetch from remote (silent, don't block on network issues)
+		fetchCmd := exec.Command("git", "-C", path, "fetch", "--quiet")
+		fetchCmd.Run() // ignore errors
+
+		return fetchCompleteMsg{path: path}
+	}
+}
+
+// fetchAllRemotes updates remote-tracking refs from every remote and prunes
+// deleted branches, without merging. Runs regardless of the auto-fetch setting.
+func fetchAllRemotes(path string) tea.Cmd {
+	return func() tea.Msg {
+		fetchLimiter.Acquire()
+		defer fetchLimiter.Release()
+
+		cmd := exec.Command("git", "-C", path, "fetch", "--all", "--prune", "--quiet")
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		err := cmd.Run()
+
+		return fetchCompleteMsg{path: path, failed: err != nil}
+	}
+}
+
+// fetchInsteadOfPull fetches all remotes for a repo whose group pulls
+// fetch-only, reporting back like a pull so batch pulls can include it
+func fetchInsteadOfPull(path string) tea.Cmd {
+	return func() tea.Msg {
+		start := time.Now()
+		output, err := exec.Command("git", "-C", path, "fetch", "--all", "--prune").CombinedOutput()
+		return pullCompleteMsg{
 			path:        path,
-			branch:      branch,
-			status:      StatusDirty,
-			text:        fmt.Sprintf("%d changed", lineCount),
-			behindCount: behindCount,
+			result:      strings.TrimSpace(string(output)),
+			shortResult: "fetched",
+			err:         err,
+			elapsed:     time.Since(start),
+			fetchOnly:   true,
+		}
+	}
+}
+
+// previewPulls measures how far each repo's branch is from its upstream,
+// without merging anything. Run it after fetching so the counts are current.
+func previewPulls(repos []Repo) tea.Cmd {
+	return func() tea.Msg {
+		entries := make([]PullPreviewEntry, len(repos))
+		for i, repo := range repos {
+			entry := PullPreviewEntry{RepoPath: repo.Path, RepoName: repo.Name}
+			if err := exec.Command("git", "-C", repo.Path, "rev-parse", "--abbrev-ref", "@{u}").Run(); err == nil {
+				entry.HasUpstream = true
+				entry.Ahead, entry.Behind = aheadBehind(repo.Path, "HEAD", "@{u}")
+			}
+			entry.Dirty = hasUncommittedChanges(repo.Path)
+			entries[i] = entry
 		}
+		return pullPreviewMsg{entries: entries}
 	}
 }
 
-func loadGitDetail(path string) tea.Cmd {
+// loadGitDetail loads the status pane of the detail view, showing up to
+// logCount recent and incoming commits
+func loadGitDetail(path string, logCount int) tea.Cmd {
 	return func() tea.Msg {
 		var sb strings.Builder
 
-		// Get full status
-		statusCmd := exec.Command("git", "-C", path, "status", "--short", "--branch")
+		// Get branch line and changed files
+		statusCmd := exec.Command("git", "-C", path, "status", "--porcelain", "--branch")
 		statusOut, _ := statusCmd.Output()
-		sb.WriteString("--- Status ---\n")
-		sb.WriteString(string(statusOut))
+		statusLine, porcelain, _ := strings.Cut(string(statusOut), "\n")
+		files := parseChangedFiles(porcelain)
 
 		// If there are changes, show diff stat
 		diffCmd := exec.Command("git", "-C", path, "diff", "--stat")
@@ -146,7 +426,8 @@ func loadGitDetail(path string) tea.Cmd {
 		}
 
 		// Show recent local commits
-		logCmd := exec.Command("git", "-C", path, "log", "--oneline", "-10", "--pretty=format:%C(yellow)%h%C(reset) %s %C(dim)(%cr)%C(reset)")
+		logLimit := fmt.Sprintf("-%d", logCount)
+		logCmd := exec.Command("git", "-C", path, "log", "--oneline", logLimit, "--pretty=format:%C(yellow)%h%C(reset) %s %C(dim)(%cr)%C(reset)")
 		logOut, _ := logCmd.Output()
 		if len(logOut) > 0 {
 			sb.WriteString("\n--- Recent Commits ---\n")
@@ -154,8 +435,16 @@ func loadGitDetail(path string) tea.Cmd {
 			sb.WriteString("\n")
 		}
 
+		// Show configured remotes
+		remoteCmd := exec.Command("git", "-C", path, "remote", "-v")
+		remoteOut, _ := remoteCmd.Output()
+		if remotes := formatRemotes(string(remoteOut)); remotes != "" {
+			sb.WriteString("\n--- Remotes ---\n")
+			sb.WriteString(remotes)
+		}
+
 		// Show incoming commits from remote (if any)
-		incomingCmd := exec.Command("git", "-C", path, "log", "--oneline", "-10", "--pretty=format:%C(green)%h%C(reset) %s %C(dim)(%cr)%C(reset)", "HEAD..@{u}")
+		incomingCmd := exec.Command("git", "-C", path, "log", "--oneline", logLimit, "--pretty=format:%C(green)%h%C(reset) %s %C(dim)(%cr)%C(reset)", "HEAD..@{u}")
 		incomingOut, _ := incomingCmd.Output()
 		if len(incomingOut) > 0 {
 			sb.WriteString("\n--- Incoming from Remote ---\n")
@@ -165,27 +454,293 @@ func loadGitDetail(path string) tea.Cmd {
 
 		return detailLoadedMsg{
 			path:    path,
+			status:  strings.TrimSpace(statusLine),
 			content: sb.String(),
+			files:   files,
+		}
+	}
+}
+
+// changeSummary describes a dirty working tree, e.g. "2 staged, 3 modified,
+// 1 untracked". A file with staged and unstaged changes counts in both.
+func changeSummary(files []ChangedFile) string {
+	var staged, modified, untracked int
+	for _, f := range files {
+		if f.Untracked() {
+			untracked++
+			continue
+		}
+		if f.Staged() {
+			staged++
+		}
+		if f.Unstaged() {
+			modified++
+		}
+	}
+	var parts []string
+	if staged > 0 {
+		parts = append(parts, fmt.Sprintf("%d staged", staged))
+	}
+	if modified > 0 {
+		parts = append(parts, fmt.Sprintf("%d modified", modified))
+	}
+	if untracked > 0 {
+		parts = append(parts, fmt.Sprintf("%d untracked", untracked))
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("%d changed", len(files))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseChangedFiles parses `git status --porcelain` lines ("XY path").
+// Renames ("R  old -> new") use the new path; quoted paths are unquoted.
+func parseChangedFiles(porcelain string) []ChangedFile {
+	var files []ChangedFile
+	for _, line := range strings.Split(porcelain, "\n") {
+		if len(line) < 4 || strings.HasPrefix(line, "##") {
+			continue
+		}
+		name := line[3:]
+		if _, to, ok := strings.Cut(name, " -> "); ok {
+			name = to
+		}
+		if unquoted, err := strconv.Unquote(name); err == nil {
+			name = unquoted
+		}
+		files = append(files, ChangedFile{Path: name, Index: line[0], Worktree: line[1]})
+	}
+	return files
+}
+
+// loadFileDiff loads the staged and unstaged diff of a single file.
+// Untracked files are diffed against /dev/null so their content shows up.
+func loadFileDiff(path, file string) tea.Cmd {
+	return func() tea.Msg {
+		var sb strings.Builder
+
+		stagedCmd := exec.Command("git", "-C", path, "diff", "--color=always", "--cached", "--", file)
+		stagedOut, _ := stagedCmd.Output()
+		if len(stagedOut) > 0 {
+			sb.WriteString("--- Staged ---\n")
+			sb.WriteString(string(stagedOut))
+		}
+
+		unstagedCmd := exec.Command("git", "-C", path, "diff", "--color=always", "--", file)
+		unstagedOut, _ := unstagedCmd.Output()
+		if len(unstagedOut) > 0 {
+			if sb.Len() > 0 {
+				sb.WriteString("\n")
+			}
+			sb.WriteString("--- Unstaged ---\n")
+			sb.WriteString(string(unstagedOut))
+		}
+
+		if sb.Len() == 0 {
+			// --no-index exits 1 when the files differ, so ignore the error
+			newCmd := exec.Command("git", "-C", path, "diff", "--color=always", "--no-index", "--", os.DevNull, file)
+			newOut, _ := newCmd.Output()
+			if len(newOut) > 0 {
+				sb.WriteString("--- Untracked ---\n")
+				sb.WriteString(string(newOut))
+			}
+		}
+
+		content := sb.String()
+		if content == "" {
+			content = "No changes to show for " + file
+		}
+		return fileDiffLoadedMsg{
+			path:    path,
+			file:    file,
+			content: content,
+		}
+	}
+}
+
+// toggleStage stages a file with unstaged changes (or untracked), otherwise
+// unstages it
+func toggleStage(path string, file ChangedFile) tea.Cmd {
+	return func() tea.Msg {
+		stage := file.Unstaged() || file.Untracked()
+		var cmd *exec.Cmd
+		if stage {
+			cmd = exec.Command("git", "-C", path, "add", "--", file.Path)
+		} else {
+			cmd = exec.Command("git", "-C", path, "reset", "-q", "HEAD", "--", file.Path)
+		}
+		output, err := cmd.CombinedOutput()
+
+		errText := ""
+		if err != nil {
+			errText = strings.TrimSpace(string(output))
+		}
+		return stageToggledMsg{
+			path:   path,
+			file:   file.Path,
+			staged: stage,
+			err:    errText,
+		}
+	}
+}
+
+// formatRemotes condenses `git remote -v` output to one line per remote.
+// A push URL that differs from the fetch URL is shown alongside it.
+func formatRemotes(output string) string {
+	var names []string
+	fetchURLs := make(map[string]string)
+	pushURLs := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		name, url := fields[0], fields[1]
+		if _, seen := fetchURLs[name]; !seen {
+			if _, seen := pushURLs[name]; !seen {
+				names = append(names, name)
+			}
+		}
+		if len(fields) >= 3 && fields[2] == "(push)" {
+			pushURLs[name] = url
+		} else {
+			fetchURLs[name] = url
+		}
+	}
+
+	var sb strings.Builder
+	for _, name := range names {
+		url := fetchURLs[name]
+		if url == "" {
+			url = pushURLs[name]
+		}
+		sb.WriteString(name + "\t" + url)
+		if push := pushURLs[name]; push != "" && push != url {
+			sb.WriteString(" (push: " + push + ")")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func loadReflog(path, branch string) tea.Cmd {
+	return func() tea.Msg {
+		// Detached HEAD has no branch reflog, fall back to HEAD's
+		ref := branch
+		if ref == "" || ref == "HEAD" || ref == "?" {
+			ref = "HEAD"
+		}
+
+		cmd := exec.Command("git", "-C", path, "reflog", "show", "-100", "--pretty=format:%C(yellow)%h%C(reset) %gd: %gs %C(dim)(%cr)%C(reset)", ref)
+		output, err := cmd.CombinedOutput()
+
+		content := string(output)
+		if err != nil {
+			content = "Failed to load reflog:\n\n" + strings.TrimSpace(content)
+		} else if strings.TrimSpace(content) == "" {
+			content = "Reflog is empty for " + ref
+		}
+
+		return reflogLoadedMsg{
+			path:    path,
+			content: content,
 		}
 	}
 }
 
-func pullRepo(path string) tea.Cmd {
+// loadDiskUsage measures a repo's working tree and object store. It walks
+// the whole tree, so it only runs when asked for from the detail view.
+func loadDiskUsage(path string) tea.Cmd {
 	return func() tea.Msg {
-		cmd := exec.Command("git", "-C", path, "pull", "--ff-only")
+		msg := diskUsageMsg{path: path}
+		msg.err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil // unreadable entries don't count
+			}
+			if d.IsDir() && d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if d.Type().IsRegular() {
+				if info, err := d.Info(); err == nil {
+					msg.worktree += info.Size()
+				}
+			}
+			return nil
+		})
+		if msg.err != nil {
+			return msg
+		}
+
+		output, err := exec.Command("git", "-C", path, "count-objects", "-v").Output()
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		msg.objects = parseCountObjects(string(output))
+		return msg
+	}
+}
+
+// parseCountObjects sums the loose and packed sizes (in KiB) reported by
+// `git count-objects -v`, returning bytes
+func parseCountObjects(output string) int64 {
+	var total int64
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok || (key != "size" && key != "size-pack") {
+			continue
+		}
+		if kib, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil {
+			total += kib * 1024
+		}
+	}
+	return total
+}
+
+// formatBytes renders a size with a binary unit, e.g. "512 B" or "1.4 GiB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// pullRepo pulls the current branch. Without autostash it only fast-forwards;
+// with it, local changes are stashed around a rebase onto the upstream.
+func pullRepo(path string, autostash bool) tea.Cmd {
+	return func() tea.Msg {
+		start := time.Now()
+		args := []string{"-C", path, "pull", "--ff-only"}
+		if autostash {
+			args = []string{"-C", path, "pull", "--rebase", "--autostash"}
+		}
+		cmd := exec.Command("git", args...)
 		output, err := cmd.CombinedOutput()
+		elapsed := time.Since(start)
 
 		result := strings.TrimSpace(string(output))
 		shortResult := result
 
+		// git reports a conflicting stash reapply but still exits 0
+		conflicted := autostash && strings.Contains(result, "Applying autostash resulted in conflicts")
+		if conflicted && err == nil {
+			err = fmt.Errorf("autostash conflict")
+		}
+
 		// Only shorten for success display in list
 		if err == nil {
 			if strings.Contains(result, "Already up to date") {
 				shortResult = "up to date"
-			} else if strings.Contains(result, "Fast-forward") {
+			} else if strings.Contains(result, "Fast-forward") || strings.Contains(result, "Successfully rebased") {
 				shortResult = "updated"
-			} else if len(result) > 30 {
-				shortResult = result[:30] + "..."
+			} else {
+				shortResult = truncateRunes(result, 30)
 			}
 		}
 
@@ -194,15 +749,73 @@ func pullRepo(path string) tea.Cmd {
 			result:      result,      // full result for error view
 			shortResult: shortResult, // short result for list display
 			err:         err,
+			elapsed:     elapsed,
+			conflicted:  conflicted,
+		}
+	}
+}
+
+// pulledNewCommits reports whether a pull merged anything, so post-pull
+// hooks should run
+func pulledNewCommits(msg pullCompleteMsg) bool {
+	return msg.err == nil && !msg.fetchOnly && !strings.Contains(msg.result, "Already up to date")
+}
+
+// truncateRunes shortens s to at most max runes, appending "..." if cut.
+// Slicing by runes keeps multibyte characters intact.
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "..."
+}
+
+// aheadBehind counts commits on branch not on upstream (ahead) and vice versa (behind)
+func aheadBehind(path, branch, upstream string) (int, int) {
+	cmd := exec.Command("git", "-C", path, "rev-list", "--left-right", "--count", branch+"..."+upstream)
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, 0
+	}
+	parts := strings.Fields(string(out))
+	if len(parts) != 2 {
+		return 0, 0
+	}
+	ahead, _ := strconv.Atoi(parts[0])
+	behind, _ := strconv.Atoi(parts[1])
+	return ahead, behind
+}
+
+// defaultBranch returns the repo's default branch from origin/HEAD, falling
+// back to a common name among the known branches
+func defaultBranch(path string, branches map[string]bool) string {
+	out, err := exec.Command("git", "-C", path, "symbolic-ref", "--short", "refs/remotes/origin/HEAD").Output()
+	if err == nil {
+		if name := strings.TrimPrefix(strings.TrimSpace(string(out)), "origin/"); name != "" {
+			return name
+		}
+	}
+	return guessDefaultBranch(branches)
+}
+
+// guessDefaultBranch picks the first conventional default branch name present
+func guessDefaultBranch(branches map[string]bool) string {
+	for _, name := range []string{"main", "master", "develop", "trunk"} {
+		if branches[name] {
+			return name
 		}
 	}
+	return ""
 }
 
 func loadBranches(path string) tea.Cmd {
 	return func() tea.Msg {
 		// Fetch from remote to get latest branches
+		fetchLimiter.Acquire()
 		fetchCmd := exec.Command("git", "-C", path, "fetch", "--all", "--prune", "--quiet")
 		fetchCmd.Run() // ignore errors
+		fetchLimiter.Release()
 
 		// Get current branch
 		currentCmd := exec.Command("git", "-C", path, "rev-parse", "--abbrev-ref", "HEAD")
@@ -245,9 +858,14 @@ func loadBranches(path string) tea.Cmd {
 		// Add local branches
 		for localName, remoteName := range localBranches {
 			hasRemote := false
+			ahead, behind := 0, 0
+			noUpstream := remoteName == ""
 			if remoteName != "" {
 				hasRemote = remoteBranches[remoteName]
 				seenRemotes[remoteName] = true
+				if hasRemote {
+					ahead, behind = aheadBehind(path, localName, remoteName)
+				}
 			} else {
 				// Check if origin/<name> exists
 				possibleRemote := "origin/" + localName
@@ -264,6 +882,9 @@ func loadBranches(path string) tea.Cmd {
 				IsRemote:   hasRemote,
 				IsCurrent:  localName == current,
 				RemoteName: remoteName,
+				Ahead:      ahead,
+				Behind:     behind,
+				NoUpstream: noUpstream,
 			})
 		}
 
@@ -287,6 +908,16 @@ func loadBranches(path string) tea.Cmd {
 			})
 		}
 
+		// Mark the default branch
+		names := make(map[string]bool, len(branches))
+		for _, b := range branches {
+			names[b.Name] = true
+		}
+		defaultName := defaultBranch(path, names)
+		for i := range branches {
+			branches[i].IsDefault = branches[i].Name == defaultName
+		}
+
 		// Sort branches: current first, then local+remote, then local-only, then remote-only
 		sort.Slice(branches, func(i, j int) bool {
 			if branches[i].IsCurrent {
@@ -309,9 +940,10 @@ func loadBranches(path string) tea.Cmd {
 		})
 
 		return branchesLoadedMsg{
-			path:     path,
-			branches: branches,
-			current:  current,
+			path:          path,
+			branches:      branches,
+			current:       current,
+			defaultBranch: defaultName,
 		}
 	}
 }
@@ -339,6 +971,57 @@ func switchBranch(path, branch string) tea.Cmd {
 	}
 }
 
+// inProgressOp reports a merge or rebase that was started but not finished:
+// "merge", "rebase" or "" when neither
+func inProgressOp(path string) string {
+	output, err := exec.Command("git", "-C", path, "rev-parse", "--git-path", "MERGE_HEAD", "--git-path", "rebase-merge", "--git-path", "rebase-apply").Output()
+	if err != nil {
+		return ""
+	}
+	for i, p := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(path, p)
+		}
+		if _, err := os.Stat(p); err == nil {
+			if i == 0 {
+				return "merge"
+			}
+			return "rebase"
+		}
+	}
+	return ""
+}
+
+// abortOperation runs `git merge --abort` or `git rebase --abort`
+func abortOperation(path, op string) tea.Cmd {
+	return func() tea.Msg {
+		output, err := exec.Command("git", "-C", path, op, "--abort").CombinedOutput()
+		msg := opAbortedMsg{path: path, op: op}
+		if err != nil {
+			msg.err = strings.TrimSpace(string(output))
+			if msg.err == "" {
+				msg.err = err.Error()
+			}
+		}
+		return msg
+	}
+}
+
+// rebaseBranch rebases the current branch onto another. When it stops on
+// conflicts the repo is left mid-rebase and the conflicts are counted.
+func rebaseBranch(path, onto string) tea.Cmd {
+	return func() tea.Msg {
+		output, err := exec.Command("git", "-C", path, "rebase", onto).CombinedOutput()
+		msg := rebaseResultMsg{path: path, onto: onto, output: strings.TrimSpace(string(output)), err: err}
+		if err != nil {
+			if status, statusErr := exec.Command("git", "-C", path, "status", "--porcelain").Output(); statusErr == nil {
+				msg.conflicts = countConflicts(string(status))
+			}
+		}
+		return msg
+	}
+}
+
 func deleteBranch(path, branch string, force bool) tea.Cmd {
 	return func() tea.Msg {
 		flag := "-d"
@@ -357,42 +1040,196 @@ func deleteBranch(path, branch string, force bool) tea.Cmd {
 			}
 		}
 
+		// "Deleted branch feature (was 1a2b3c4)."
+		tip := ""
+		if _, rest, ok := strings.Cut(string(output), "(was "); ok {
+			tip, _, _ = strings.Cut(rest, ")")
+		}
+
 		return branchDeleteMsg{
 			path:    path,
 			branch:  branch,
 			success: true,
 			err:     "",
+			tip:     tip,
 		}
 	}
 }
 
-func createLocalBranch(path, localName, remoteName string) tea.Cmd {
+// unmergedCommits counts the commits on a local branch that HEAD doesn't
+// contain. Zero means the branch is merged and deleting it loses nothing.
+func unmergedCommits(path, branch string) (int, error) {
+	out, err := exec.Command("git", "-C", path, "branch", "--merged", "HEAD", "--list", branch).Output()
+	if err != nil {
+		return 0, err
+	}
+	if strings.TrimSpace(string(out)) != "" {
+		return 0, nil
+	}
+	out, err = exec.Command("git", "-C", path, "rev-list", "--count", "HEAD..refs/heads/"+branch).Output()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
+
+// checkUnmerged counts a branch's unmerged commits before a force delete
+func checkUnmerged(path, branch string) tea.Cmd {
 	return func() tea.Msg {
-		// Create local branch tracking the remote branch
-		cmd := exec.Command("git", "-C", path, "branch", "--track", localName, remoteName)
+		unmerged, err := unmergedCommits(path, branch)
+		return unmergedCheckedMsg{path: path, branch: branch, unmerged: unmerged, err: err}
+	}
+}
+
+// deleteRemoteBranch deletes a branch on the remote via git push --delete
+func deleteRemoteBranch(path, remote, branch string) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("git", "-C", path, "push", remote, "--delete", branch)
 		output, err := cmd.CombinedOutput()
 
 		if err != nil {
-			return branchCreateMsg{
+			return remoteBranchDeleteMsg{
 				path:    path,
-				branch:  localName,
+				remote:  remote,
+				branch:  branch,
 				success: false,
 				err:     strings.TrimSpace(string(output)),
 			}
 		}
 
-		return branchCreateMsg{
+		return remoteBranchDeleteMsg{
 			path:    path,
-			branch:  localName,
+			remote:  remote,
+			branch:  branch,
+			success: true,
+			err:     "",
+		}
+	}
+}
+
+// checkUpstream looks up the current branch's upstream and how many local
+// commits aren't on it, so a reset to it can be confirmed
+func checkUpstream(path string) tea.Cmd {
+	return func() tea.Msg {
+		out, err := exec.Command("git", "-C", path, "rev-parse", "--abbrev-ref", "@{u}").CombinedOutput()
+		if err != nil {
+			return upstreamCheckedMsg{path: path, err: errors.New(strings.TrimSpace(string(out)))}
+		}
+		upstream := strings.TrimSpace(string(out))
+		out, err = exec.Command("git", "-C", path, "rev-list", "--count", "@{u}..HEAD").Output()
+		if err != nil {
+			return upstreamCheckedMsg{path: path, err: err}
+		}
+		ahead, _ := strconv.Atoi(strings.TrimSpace(string(out)))
+		return upstreamCheckedMsg{path: path, upstream: upstream, ahead: ahead}
+	}
+}
+
+// resetToUpstream discards local commits and changes, matching the upstream
+func resetToUpstream(path string) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("git", "-C", path, "reset", "--hard", "@{u}")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return resetHardMsg{path: path, success: false, err: strings.TrimSpace(string(output))}
+		}
+		return resetHardMsg{path: path, success: true}
+	}
+}
+
+func createLocalBranch(path, localName, remoteName string) tea.Cmd {
+	return func() tea.Msg {
+		// Create local branch tracking the remote branch
+		cmd := exec.Command("git", "-C", path, "branch", "--track", localName, remoteName)
+		output, err := cmd.CombinedOutput()
+
+		if err != nil {
+			return branchCreateMsg{
+				path:    path,
+				branch:  localName,
+				success: false,
+				err:     strings.TrimSpace(string(output)),
+			}
+		}
+
+		return branchCreateMsg{
+			path:    path,
+			branch:  localName,
+			success: true,
+			err:     "",
+		}
+	}
+}
+
+// setUpstream makes branch track upstream, e.g. "origin/<branch>"
+func setUpstream(path, branch, upstream string) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("git", "-C", path, "branch", "--set-upstream-to", upstream, branch)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return upstreamSetMsg{path: path, branch: branch, upstream: upstream, success: false, err: strings.TrimSpace(string(output))}
+		}
+		return upstreamSetMsg{path: path, branch: branch, upstream: upstream, success: true}
+	}
+}
+
+// createBranch creates a new branch from HEAD and checks it out
+func createBranch(path, name string) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("git", "-C", path, "checkout", "-b", name)
+		output, err := cmd.CombinedOutput()
+
+		if err != nil {
+			return branchCreateMsg{
+				path:     path,
+				branch:   name,
+				checkout: true,
+				success:  false,
+				err:      strings.TrimSpace(string(output)),
+			}
+		}
+
+		return branchCreateMsg{
+			path:     path,
+			branch:   name,
+			checkout: true,
+			success:  true,
+			err:      "",
+		}
+	}
+}
+
+func renameBranch(path, oldName, newName string) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("git", "-C", path, "branch", "-m", oldName, newName)
+		output, err := cmd.CombinedOutput()
+
+		if err != nil {
+			return branchRenameMsg{
+				path:    path,
+				oldName: oldName,
+				newName: newName,
+				success: false,
+				err:     strings.TrimSpace(string(output)),
+			}
+		}
+
+		return branchRenameMsg{
+			path:    path,
+			oldName: oldName,
+			newName: newName,
 			success: true,
 			err:     "",
 		}
 	}
 }
 
+// autoStashMessage tags stashes guppi creates before switching branches
+const autoStashMessage = "guppi: auto-stash before branch switch"
+
 func stashChanges(path string) tea.Cmd {
 	return func() tea.Msg {
-		cmd := exec.Command("git", "-C", path, "stash", "push", "-m", "guppi: auto-stash before branch switch")
+		cmd := exec.Command("git", "-C", path, "stash", "push", "-m", autoStashMessage)
 		output, err := cmd.CombinedOutput()
 
 		if err != nil {
@@ -411,6 +1248,115 @@ func stashChanges(path string) tea.Cmd {
 	}
 }
 
+func loadStashes(path string) tea.Cmd {
+	return func() tea.Msg {
+		// NUL-separated: stash messages can contain any printable character
+		cmd := exec.Command("git", "-C", path, "stash", "list", "--format=%gd%x00%gs%x00%cr")
+		output, _ := cmd.Output()
+
+		var stashes []StashInfo
+		for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			parts := strings.SplitN(line, "\x00", 3)
+			if len(parts) == 3 {
+				stashes = append(stashes, StashInfo{
+					Ref:     parts[0],
+					Message: parts[1],
+					Time:    parts[2],
+				})
+			}
+		}
+
+		return stashesLoadedMsg{
+			path:    path,
+			stashes: stashes,
+		}
+	}
+}
+
+// applyStash applies a stash entry, removing it from the list if pop is set
+func applyStash(path, ref string, pop bool) tea.Cmd {
+	return func() tea.Msg {
+		action := "apply"
+		if pop {
+			action = "pop"
+		}
+		cmd := exec.Command("git", "-C", path, "stash", action, ref)
+		output, err := cmd.CombinedOutput()
+
+		if err != nil {
+			return stashActionMsg{
+				path:    path,
+				action:  action,
+				ref:     ref,
+				success: false,
+				err:     strings.TrimSpace(string(output)),
+			}
+		}
+
+		return stashActionMsg{
+			path:    path,
+			action:  action,
+			ref:     ref,
+			success: true,
+			err:     "",
+		}
+	}
+}
+
+// popAutoStash pops the most recent stash guppi created before a branch switch
+func popAutoStash(path string) tea.Cmd {
+	return func() tea.Msg {
+		output, err := exec.Command("git", "-C", path, "stash", "list", "--format=%gd|%gs").CombinedOutput()
+		if err != nil {
+			return autoStashPopMsg{path: path, err: strings.TrimSpace(string(output))}
+		}
+
+		// Stash subjects read "On <branch>: <message>"; the list is newest first
+		ref := ""
+		for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			r, subject, ok := strings.Cut(line, "|")
+			if ok && strings.HasSuffix(subject, ": "+autoStashMessage) {
+				ref = r
+				break
+			}
+		}
+		if ref == "" {
+			return autoStashPopMsg{path: path, notFound: true}
+		}
+
+		out, err := exec.Command("git", "-C", path, "stash", "pop", ref).CombinedOutput()
+		if err != nil {
+			return autoStashPopMsg{path: path, ref: ref, err: strings.TrimSpace(string(out))}
+		}
+		return autoStashPopMsg{path: path, ref: ref, success: true}
+	}
+}
+
+func dropStash(path, ref string) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("git", "-C", path, "stash", "drop", ref)
+		output, err := cmd.CombinedOutput()
+
+		if err != nil {
+			return stashActionMsg{
+				path:    path,
+				action:  "drop",
+				ref:     ref,
+				success: false,
+				err:     strings.TrimSpace(string(output)),
+			}
+		}
+
+		return stashActionMsg{
+			path:    path,
+			action:  "drop",
+			ref:     ref,
+			success: true,
+			err:     "",
+		}
+	}
+}
+
 func discardChanges(path string) tea.Cmd {
 	return func() tea.Msg {
 		// Reset staged changes
@@ -435,57 +1381,728 @@ func discardChanges(path string) tea.Cmd {
 	}
 }
 
+// countConflicts counts unmerged paths in `git status --porcelain` output
+// (DD, AU, UD, UA, DU, AA, UU)
+func countConflicts(porcelain string) int {
+	count := 0
+	for _, line := range strings.Split(porcelain, "\n") {
+		if len(line) < 2 {
+			continue
+		}
+		switch line[:2] {
+		case "DD", "AU", "UD", "UA", "DU", "AA", "UU":
+			count++
+		}
+	}
+	return count
+}
+
+// commitChanges commits the staged changes with the given message
+func commitChanges(path, message string) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("git", "-C", path, "commit", "-m", message)
+		output, err := cmd.CombinedOutput()
+
+		if err != nil {
+			return commitMsg{
+				path:    path,
+				success: false,
+				err:     strings.TrimSpace(string(output)),
+			}
+		}
+
+		return commitMsg{
+			path:    path,
+			output:  strings.TrimSpace(string(output)),
+			success: true,
+		}
+	}
+}
+
 func hasUncommittedChanges(path string) bool {
 	cmd := exec.Command("git", "-C", path, "status", "--porcelain")
 	output, _ := cmd.Output()
 	return strings.TrimSpace(string(output)) != ""
 }
 
+// worktreeChanges counts tracked changes and lists untracked files (each
+// file, not collapsed per directory), ignoring those .gitignore excludes
+func worktreeChanges(path string) (tracked int, untracked []string) {
+	output, _ := exec.Command("git", "-C", path, "status", "--porcelain", "--untracked-files=no").Output()
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) != "" {
+			tracked++
+		}
+	}
+	output, _ = exec.Command("git", "-C", path, "ls-files", "--others", "--exclude-standard", "-z").Output()
+	for _, name := range strings.Split(string(output), "\x00") {
+		if name != "" {
+			untracked = append(untracked, name)
+		}
+	}
+	return tracked, untracked
+}
+
+// untrackedOverwritten lists the untracked files that also exist on ref,
+// which git refuses to overwrite when checking ref out
+func untrackedOverwritten(path, ref string, untracked []string) []string {
+	if len(untracked) == 0 {
+		return nil
+	}
+	output, err := exec.Command("git", "-C", path, "ls-tree", "-r", "-z", "--name-only", ref).Output()
+	if err != nil {
+		return nil
+	}
+	return overlappingPaths(untracked, strings.Split(string(output), "\x00"))
+}
+
+// checkSwitch looks at the working tree before checking out ref, so the
+// switch can be refused or confirmed first
+func checkSwitch(path, branch, ref string) tea.Cmd {
+	return func() tea.Msg {
+		tracked, untracked := worktreeChanges(path)
+		return switchCheckedMsg{
+			path:      path,
+			branch:    branch,
+			ref:       ref,
+			tracked:   tracked,
+			untracked: untracked,
+			clobbered: untrackedOverwritten(path, ref, untracked),
+		}
+	}
+}
+
+// overlappingPaths returns the paths of a that are also in b, in a's order
+func overlappingPaths(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, p := range b {
+		inB[p] = true
+	}
+	var both []string
+	for _, p := range a {
+		if inB[p] {
+			both = append(both, p)
+		}
+	}
+	return both
+}
+
+// splitCommandLine tokenizes a command line like a POSIX shell would for
+// simple input: whitespace separates words, single quotes are literal,
+// double quotes allow \" \\ \$ and \` escapes, and a backslash outside
+// quotes escapes the next character. No expansion is performed.
+func splitCommandLine(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inWord := false
+	var quote rune // 0, '\'' or '"'
+	escaped := false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			if quote == '"' && r != '"' && r != '\\' && r != '$' && r != '`' {
+				current.WriteRune('\\')
+			}
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inWord = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// runCommand starts a command and streams its combined output line by line.
+// A cmdStartedMsg hands the process to the model (for cancelling), then each
+// line arrives as a cmdOutputLineMsg and a final cmdResultMsg follows on exit.
 func runCommand(path, command string) tea.Cmd {
 	return func() tea.Msg {
-		// Split command into parts
-		parts := strings.Fields(command)
+		parts, err := splitCommandLine(command)
+		if err != nil {
+			return cmdResultMsg{output: "", err: err}
+		}
 		if len(parts) == 0 {
 			return cmdResultMsg{output: "", err: fmt.Errorf("empty command")}
 		}
 
+		pr, pw, err := os.Pipe()
+		if err != nil {
+			return cmdResultMsg{output: "", err: err}
+		}
+
 		cmd := exec.Command(parts[0], parts[1:]...)
 		cmd.Dir = path
-		output, err := cmd.CombinedOutput()
+		cmd.Stdout = pw
+		cmd.Stderr = pw
+		// Own process group so cancelling also kills children (e.g. ssh under git push)
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+		if err := cmd.Start(); err != nil {
+			pr.Close()
+			pw.Close()
+			return cmdResultMsg{output: "", err: err}
+		}
+		pw.Close() // child holds its own copy; EOF arrives when it exits
+
+		stream := make(chan tea.Msg, 64)
+		go func() {
+			reader := bufio.NewReader(pr)
+			for {
+				line, readErr := reader.ReadString('\n')
+				if line != "" {
+					stream <- cmdOutputLineMsg{line: line, stream: stream}
+				}
+				if readErr != nil {
+					break
+				}
+			}
+			pr.Close()
+			stream <- cmdResultMsg{output: "", err: cmd.Wait()}
+			close(stream)
+		}()
+
+		return cmdStartedMsg{proc: cmd, stream: stream}
+	}
+}
+
+// hostFromURL extracts the host from a remote URL, e.g.
+// "git@github.com:org/app.git" or "https://gitlab.com/org/app" -> the host.
+// Local paths and file:// URLs have no host and return "".
+func hostFromURL(url string) string {
+	url = strings.TrimSpace(url)
+	if scheme, rest, ok := strings.Cut(url, "://"); ok {
+		if scheme == "file" {
+			return ""
+		}
+		host, _, _ := strings.Cut(rest, "/")
+		if _, h, ok := strings.Cut(host, "@"); ok {
+			host = h
+		}
+		if h, _, ok := strings.Cut(host, ":"); ok {
+			host = h
+		}
+		return strings.ToLower(host)
+	}
+	// scp-like syntax: [user@]host:path
+	host, _, ok := strings.Cut(url, ":")
+	if !ok || strings.Contains(host, "/") {
+		return ""
+	}
+	if _, h, ok := strings.Cut(host, "@"); ok {
+		host = h
+	}
+	return strings.ToLower(host)
+}
+
+// loadRemoteHosts looks up the origin host of each repo
+func loadRemoteHosts(paths []string) tea.Cmd {
+	return func() tea.Msg {
+		hosts := make(map[string]string)
+		for _, path := range paths {
+			cmd := exec.Command("git", "-C", path, "remote", "get-url", "origin")
+			out, err := cmd.Output()
+			if err != nil {
+				continue
+			}
+			if host := hostFromURL(string(out)); host != "" {
+				hosts[path] = host
+			}
+		}
+		return remoteHostsLoadedMsg{hosts: hosts}
+	}
+}
+
+// originURL reads the origin remote's URL straight from .git/config, which is
+// much cheaper than running git for every repo during a scan
+func originURL(repoPath string) string {
+	data, err := os.ReadFile(filepath.Join(repoPath, ".git", "config"))
+	if err != nil {
+		return ""
+	}
+	return parseOriginURL(string(data))
+}
+
+// parseOriginURL finds url in the [remote "origin"] section of a git config
+func parseOriginURL(config string) string {
+	inOrigin := false
+	for _, line := range strings.Split(config, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if !inOrigin {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && strings.TrimSpace(key) == "url" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// repoNameFromURL derives the directory git clone would create for a URL,
+// e.g. "git@github.com:org/app.git" -> "app"
+func repoNameFromURL(url string) string {
+	url = strings.TrimRight(strings.TrimSpace(url), "/")
+	url = strings.TrimSuffix(url, ".git")
+	if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+		url = url[i+1:]
+	}
+	return url
+}
+
+// scanProgressLines splits git progress output on \n as well as the \r
+// git uses to redraw its progress counters
+func scanProgressLines(data []byte, atEOF bool) (int, []byte, error) {
+	for i, b := range data {
+		if b == '\n' || b == '\r' {
+			return i + 1, data[:i], nil
+		}
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// cloneRepo clones url into gitDir, streaming git's progress lines
+func cloneRepo(gitDir, url string) tea.Cmd {
+	return func() tea.Msg {
+		name := repoNameFromURL(url)
+		if name == "" {
+			return cloneCompleteMsg{name: url, err: fmt.Errorf("cannot determine directory name from %q", url)}
+		}
+		target := filepath.Join(gitDir, name)
+		if _, err := os.Stat(target); err == nil {
+			return cloneCompleteMsg{path: target, name: name, err: fmt.Errorf("%s already exists", target)}
+		}
+
+		pr, pw, err := os.Pipe()
+		if err != nil {
+			return cloneCompleteMsg{path: target, name: name, err: err}
+		}
+
+		cmd := exec.Command("git", "clone", "--progress", url, target)
+		cmd.Stdout = pw
+		cmd.Stderr = pw
+		// Fail on missing credentials instead of prompting inside the TUI
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		if err := cmd.Start(); err != nil {
+			pr.Close()
+			pw.Close()
+			return cloneCompleteMsg{path: target, name: name, err: err}
+		}
+		pw.Close()
+
+		stream := make(chan tea.Msg, 64)
+		go func() {
+			var output strings.Builder
+			scanner := bufio.NewScanner(pr)
+			scanner.Split(scanProgressLines)
+			for scanner.Scan() {
+				line := strings.TrimSpace(scanner.Text())
+				if line == "" {
+					continue
+				}
+				output.WriteString(line + "\n")
+				select {
+				case stream <- cloneProgressMsg{line: line, stream: stream}:
+				default: // drop progress updates if the UI lags behind
+				}
+			}
+			pr.Close()
+			stream <- cloneCompleteMsg{path: target, name: name, output: output.String(), err: cmd.Wait()}
+			close(stream)
+		}()
+
+		return cloneProgressMsg{line: "Cloning into " + name + "...", stream: stream}
+	}
+}
 
-		return cmdResultMsg{
-			output: string(output),
-			err:    err,
+// errNoClipboard is returned when no clipboard tool is available
+var errNoClipboard = errors.New("no clipboard tool found (install pbcopy, wl-copy, xclip or xsel)")
+
+// copyToClipboard writes text to the system clipboard using the platform's
+// clipboard tool
+func copyToClipboard(text string) error {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	default:
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, []string{"wl-copy"})
 		}
+		candidates = append(candidates,
+			[]string{"xclip", "-selection", "clipboard"},
+			[]string{"xsel", "--clipboard", "--input"},
+		)
+	}
+
+	for _, c := range candidates {
+		if _, err := exec.LookPath(c[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	return errNoClipboard
+}
+
+// killCommand kills a running command's whole process group
+func killCommand(proc *exec.Cmd) error {
+	if proc == nil || proc.Process == nil {
+		return fmt.Errorf("no running command")
+	}
+	return syscall.Kill(-proc.Process.Pid, syscall.SIGKILL)
+}
+
+// waitForCmdOutput returns the next message from a running command's stream
+func waitForCmdOutput(stream chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-stream
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// runPostPullHook runs a configured post-pull command in the repo through
+// sh, so hooks can chain commands ("go mod download && make")
+func runPostPullHook(path, command string) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Dir = path
+		output, err := cmd.CombinedOutput()
+		return hookResultMsg{
+			path:    path,
+			command: command,
+			output:  string(output),
+			err:     err,
+		}
+	}
+}
+
+// hookSummary is the last line a hook printed, or "ok" if it printed nothing
+func hookSummary(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return last
 	}
+	return "ok"
 }
 
+// applyGitPrefix prepends "git " to a command pane input unless it already
+// is a git command. A leading "!" runs the rest as-is (non-git command).
+func applyGitPrefix(command string) string {
+	command = strings.TrimSpace(command)
+	if strings.HasPrefix(command, "!") {
+		return strings.TrimSpace(command[1:])
+	}
+	if command == "git" || strings.HasPrefix(command, "git ") {
+		return command
+	}
+	return "git " + command
+}
+
+// buildToolCommand builds the command for an external tool run in a repo.
+// "{path}" in the tool's arguments is replaced with the repo path.
+func buildToolCommand(tool, path string) (*exec.Cmd, error) {
+	parts, err := splitCommandLine(tool)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	if _, err := exec.LookPath(parts[0]); err != nil {
+		return nil, fmt.Errorf("%s not found in PATH", parts[0])
+	}
+
+	args := make([]string, len(parts)-1)
+	for i, arg := range parts[1:] {
+		args[i] = strings.ReplaceAll(arg, "{path}", path)
+	}
+	cmd := exec.Command(parts[0], args...)
+	cmd.Dir = path
+	return cmd, nil
+}
+
+// toolName returns the executable name of a tool command line, for display
+func toolName(tool string) string {
+	if parts, err := splitCommandLine(tool); err == nil && len(parts) > 0 {
+		return filepath.Base(parts[0])
+	}
+	return tool
+}
+
+// errNoWebURL is returned when a repo has no remote that maps to a web page
+var errNoWebURL = errors.New("no remote with a web URL (local or file:// remotes only)")
+
+// getRepoWebURL returns the https page of the repo's origin remote, falling
+// back to the first other remote that maps to a web URL
 func getRepoWebURL(path string) (string, error) {
-	cmd := exec.Command("git", "-C", path, "remote", "get-url", "origin")
-	output, err := cmd.Output()
+	output, err := exec.Command("git", "-C", path, "remote").Output()
 	if err != nil {
 		return "", err
 	}
+	remotes := strings.Fields(string(output))
+	if len(remotes) == 0 {
+		return "", fmt.Errorf("no remotes configured")
+	}
+	// Prefer origin
+	for i, r := range remotes {
+		if r == "origin" {
+			remotes[0], remotes[i] = remotes[i], remotes[0]
+			break
+		}
+	}
 
-	url := strings.TrimSpace(string(output))
+	for _, remote := range remotes {
+		out, err := exec.Command("git", "-C", path, "remote", "get-url", remote).Output()
+		if err != nil {
+			continue
+		}
+		if url, err := webURLFromRemote(string(out)); err == nil {
+			return url, nil
+		}
+	}
+	return "", errNoWebURL
+}
+
+// webURLFromRemote converts a git remote URL into the repo's web page:
+//
+//	git@github.com:user/repo.git           -> https://github.com/user/repo
+//	ssh://git@gitlab.com:2222/group/repo   -> https://gitlab.com/group/repo
+//	https://user@git.example.com:8443/repo -> https://git.example.com:8443/repo
+//
+// SSH ports are dropped since they don't serve the web UI; http(s) ports
+// are kept. Local paths and file:// remotes return errNoWebURL.
+func webURLFromRemote(remote string) (string, error) {
+	remote = strings.TrimSpace(remote)
+	host := hostFromURL(remote)
+	// A single letter is a Windows drive, not a host
+	if len(host) <= 1 || strings.Contains(host, "\\") {
+		return "", errNoWebURL
+	}
+	scheme := "https"
+	var repoPath string
+
+	if s, rest, ok := strings.Cut(remote, "://"); ok {
+		s = strings.ToLower(s)
+		hostPart, p, _ := strings.Cut(rest, "/")
+		switch s {
+		case "http", "https":
+			// Keep the port, the web UI is served there
+			scheme = s
+			if _, h, ok := strings.Cut(hostPart, "@"); ok {
+				hostPart = h // drop user or credentials
+			}
+			host = strings.ToLower(hostPart)
+		case "ssh", "git", "git+ssh", "ssh+git":
+		default:
+			return "", errNoWebURL
+		}
+		repoPath = p
+	} else {
+		_, repoPath, _ = strings.Cut(remote, ":")
+	}
 
-	// Convert SSH URL to HTTPS
-	// git@github.com:user/repo.git -> https://github.com/user/repo
-	// git@gitlab.example.com:group/repo.git -> https://gitlab.example.com/group/repo
-	if strings.HasPrefix(url, "git@") {
-		url = strings.TrimPrefix(url, "git@")
-		url = strings.TrimSuffix(url, ".git")
-		url = strings.Replace(url, ":", "/", 1)
-		url = "https://" + url
-	} else if strings.HasPrefix(url, "https://") {
-		url = strings.TrimSuffix(url, ".git")
+	repoPath = strings.Trim(repoPath, "/")
+	repoPath = strings.TrimSuffix(repoPath, ".git")
+	if repoPath == "" {
+		return "", errNoWebURL
 	}
 
-	return url, nil
+	// Azure DevOps SSH remotes use a different layout than the web UI:
+	// git@ssh.dev.azure.com:v3/org/project/repo -> https://dev.azure.com/org/project/_git/repo
+	if host == "ssh.dev.azure.com" {
+		parts := strings.Split(strings.TrimPrefix(repoPath, "v3/"), "/")
+		if len(parts) == 3 {
+			return "https://dev.azure.com/" + parts[0] + "/" + parts[1] + "/_git/" + parts[2], nil
+		}
+	}
+
+	return scheme + "://" + host + "/" + repoPath, nil
+}
+
+// branchWebURL returns the page to open for a branch of a repo whose web root
+// is repoURL. Feature branches get the host's "create pull request" page,
+// main/master get the branch tree. Unknown hosts fall back to the repo root.
+func branchWebURL(repoURL, branch string) string {
+	if branch == "" || branch == "HEAD" {
+		return repoURL
+	}
+	trunk := branch == "main" || branch == "master"
+	host := hostFromURL(repoURL)
+	switch {
+	case strings.Contains(host, "github"):
+		if trunk {
+			return repoURL + "/tree/" + branch
+		}
+		return repoURL + "/compare/" + branch + "?expand=1"
+	case strings.Contains(host, "gitlab"):
+		if trunk {
+			return repoURL + "/-/tree/" + branch
+		}
+		return repoURL + "/-/merge_requests/new?merge_request%5Bsource_branch%5D=" + url.QueryEscape(branch)
+	case strings.Contains(host, "bitbucket"):
+		if trunk {
+			return repoURL + "/src/" + branch
+		}
+		return repoURL + "/pull-requests/new?source=" + url.QueryEscape(branch)
+	}
+	return repoURL
 }
 
 func openInBrowser(url string) error {
-	return exec.Command("open", url).Start()
+	return openWithSystem(url)
+}
+
+// openInFileManager reveals a directory in the OS file manager
+func openInFileManager(path string) error {
+	return openWithSystem(path)
+}
+
+// openWithSystem hands a URL or path to the platform's default opener
+func openWithSystem(target string) error {
+	var opener string
+	switch runtime.GOOS {
+	case "darwin":
+		opener = "open"
+	case "windows":
+		opener = "explorer"
+	default:
+		opener = "xdg-open"
+	}
+	if _, err := exec.LookPath(opener); err != nil {
+		return fmt.Errorf("%s not found", opener)
+	}
+	return exec.Command(opener, target).Start()
+}
+
+// formatElapsed renders an operation's duration, e.g. "3.2s" or "1m5s"
+func formatElapsed(d time.Duration) string {
+	if d < 100*time.Millisecond {
+		return d.Round(time.Millisecond).String()
+	}
+	if d < time.Minute {
+		return d.Round(100 * time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
+
+// parseDescribe splits `git describe --tags --long` output like
+// "v1.2.0-3-gabc1234" into the tag and the number of commits since it
+func parseDescribe(desc string) (string, int) {
+	rest, _, ok := cutLast(desc, "-")
+	if !ok {
+		return "", 0
+	}
+	tag, count, ok := cutLast(rest, "-")
+	if !ok {
+		return "", 0
+	}
+	distance, err := strconv.Atoi(count)
+	if err != nil {
+		return "", 0
+	}
+	return tag, distance
+}
+
+// cutLast is strings.Cut around the last occurrence of sep
+func cutLast(s, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
+
+// gitErrorText extracts what git printed on stderr from a failed command run
+// with Output, falling back to the exit error itself
+func gitErrorText(err error) string {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if stderr := strings.TrimSpace(string(exitErr.Stderr)); stderr != "" {
+			return stderr
+		}
+	}
+	return err.Error()
+}
+
+// isDubiousOwnership reports whether git refused a repo owned by another user
+func isDubiousOwnership(output string) bool {
+	return strings.Contains(output, "detected dubious ownership")
+}
+
+// addSafeDirectory marks a repo owned by another user as trusted in the
+// global git config, so git stops refusing to operate on it
+func addSafeDirectory(path string) tea.Cmd {
+	return func() tea.Msg {
+		out, err := exec.Command("git", "config", "--global", "--add", "safe.directory", path).CombinedOutput()
+		if err != nil {
+			err = errors.New(strings.TrimSpace(string(out)))
+		}
+		return safeDirectoryAddedMsg{path: path, err: err}
+	}
+}
+
+// formatAge renders how long ago something happened, e.g. "2d ago"
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo ago", int(d/(30*24*time.Hour)))
+	}
+	return fmt.Sprintf("%dy ago", int(d/(365*24*time.Hour)))
 }
 
 // getHeadCommit returns the current HEAD commit hash
@@ -504,21 +2121,27 @@ func getCommitsBetween(path, oldRef, newRef string) []CommitInfo {
 		return nil
 	}
 
-	// Get commits with format: hash|subject|author|relative time
-	cmd := exec.Command("git", "-C", path, "log", "--pretty=format:%h|%s|%an|%ar", oldRef+".."+newRef)
+	// NUL-separated so subjects containing any printable character parse safely
+	cmd := exec.Command("git", "-C", path, "log", "--pretty=format:%h%x00%s%x00%an%x00%cr", oldRef+".."+newRef)
 	output, err := cmd.Output()
 	if err != nil {
 		return nil
 	}
 
-	lines := strings.TrimSpace(string(output))
+	return parseCommitLog(string(output))
+}
+
+// parseCommitLog parses `git log --pretty=format:%h%x00%s%x00%an%x00%cr`
+// output, one commit per line
+func parseCommitLog(output string) []CommitInfo {
+	lines := strings.TrimSpace(output)
 	if lines == "" {
 		return nil
 	}
 
 	var commits []CommitInfo
 	for _, line := range strings.Split(lines, "\n") {
-		parts := strings.SplitN(line, "|", 4)
+		parts := strings.SplitN(line, "\x00", 4)
 		if len(parts) >= 4 {
 			commits = append(commits, CommitInfo{
 				Hash:    parts[0],
@@ -531,6 +2154,42 @@ func getCommitsBetween(path, oldRef, newRef string) []CommitInfo {
 	return commits
 }
 
+// commitLogPageSize is how many commits the commit log loads at a time
+const commitLogPageSize = 100
+
+// loadCommitLog loads a page of HEAD's history after the skip commits already
+// shown. A query keeps only commits whose message contains it (any case).
+func loadCommitLog(path, query string, skip int) tea.Cmd {
+	return func() tea.Msg {
+		args := []string{"-C", path, "log", "--pretty=format:%h%x00%s%x00%an%x00%cr", fmt.Sprintf("--skip=%d", skip), fmt.Sprintf("-%d", commitLogPageSize)}
+		if query != "" {
+			args = append(args, "--regexp-ignore-case", "--fixed-strings", "--grep="+query)
+		}
+		msg := commitLogLoadedMsg{path: path, query: query, skip: skip}
+		output, err := exec.Command("git", args...).Output()
+		if err != nil {
+			msg.err = gitErrorText(err)
+			return msg
+		}
+		msg.commits = parseCommitLog(string(output))
+		return msg
+	}
+}
+
+// loadCommitShow loads a commit's full message and diffstat
+func loadCommitShow(path, hash string) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("git", "-C", path, "show", "--stat", "--format=fuller", "--color=always", hash)
+		output, err := cmd.CombinedOutput()
+
+		content := string(output)
+		if err != nil {
+			content = "Failed to load commit:\n\n" + strings.TrimSpace(content)
+		}
+		return commitShowLoadedMsg{path: path, hash: hash, content: content}
+	}
+}
+
 // getFilesChangedCount returns number of files changed between two refs
 func getFilesChangedCount(path, oldRef, newRef string) int {
 	if oldRef == "" || newRef == "" || oldRef == newRef {
@@ -559,4 +2218,3 @@ func getFilesChangedCount(path, oldRef, newRef string) int {
 	}
 	return 0
 }
-
@@ -2,8 +2,12 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 )
 
 // FetchMode determines how repo status is fetched
@@ -15,14 +19,92 @@ const (
 	FetchFavorites                  // Only fetch favorites
 )
 
+// fetchModeName names a fetch mode for the group settings view
+func fetchModeName(mode FetchMode) string {
+	switch mode {
+	case FetchOnDemand:
+		return "on-demand"
+	case FetchFavorites:
+		return "favorites only"
+	default:
+		return "all repos"
+	}
+}
+
+// Pull strategies a group can use instead of the global pull setting
+const (
+	PullFFOnly    = "ff-only"
+	PullRebase    = "rebase"     // git pull --rebase --autostash
+	PullFetchOnly = "fetch-only" // fetch all remotes without merging
+)
+
+// pullStrategies are the values a group's pull strategy cycles through,
+// "" meaning the global setting
+var pullStrategies = []string{"", PullFFOnly, PullRebase, PullFetchOnly}
+
+// SortMode determines how repos are ordered in the list
+type SortMode int
+
+const (
+	SortByName   SortMode = iota // Favorites first, then alphabetical (default)
+	SortByStatus                 // Dirty first, then behind, errors, clean
+	SortByBehind                 // Most commits behind first
+	SortByRecent                 // Most recent commit first
+	sortModeCount
+)
+
+func (s SortMode) String() string {
+	switch s {
+	case SortByStatus:
+		return "status"
+	case SortByBehind:
+		return "behind"
+	case SortByRecent:
+		return "recent"
+	default:
+		return "name"
+	}
+}
+
 // Config holds application configuration
 type Config struct {
-	GitDir            string    `json:"gitDir"`
-	SetupComplete     bool      `json:"setupComplete"`
-	FetchMode         FetchMode `json:"fetchMode"`
-	BinaryPath        string    `json:"binaryPath,omitempty"`
-	ShowPullResults   *bool     `json:"showPullResults,omitempty"`   // nil = true (default)
-	MaxCommitsPerRepo int       `json:"maxCommitsPerRepo,omitempty"` // 0 = 5 (default)
+	GitDir               string            `json:"gitDir"`
+	SetupComplete        bool              `json:"setupComplete"`
+	FetchMode            FetchMode         `json:"fetchMode"`
+	SortMode             SortMode          `json:"sortMode,omitempty"`
+	FilterDirty          bool              `json:"filterDirty,omitempty"`    // status filter: only repos with local changes
+	FilterBehind         bool              `json:"filterBehind,omitempty"`   // status filter: only repos behind remote
+	FilterDetached       bool              `json:"filterDetached,omitempty"` // status filter: only repos with a detached HEAD
+	FilterConflict       bool              `json:"filterConflict,omitempty"` // status filter: only repos with merge conflicts
+	HideClean            bool              `json:"hideClean,omitempty"`      // status filter: hide clean, up-to-date repos
+	BinaryPath           string            `json:"binaryPath,omitempty"`
+	ShowPullResults      *bool             `json:"showPullResults,omitempty"`      // nil = true (default)
+	MaxCommitsPerRepo    int               `json:"maxCommitsPerRepo,omitempty"`    // 0 = 5 (default)
+	DetailLogCount       int               `json:"detailLogCount,omitempty"`       // 0 = 10 (default); commits shown in the detail status pane
+	DetailStatusWidthPct int               `json:"detailStatusWidthPct,omitempty"` // 0 = 60 (default); status pane share of the detail view width, 30-80
+	DetailCmdHeight      int               `json:"detailCmdHeight,omitempty"`      // 0 = 6 (default); command pane height in lines, 4-20
+	MaxConcurrentFetches int               `json:"maxConcurrentFetches,omitempty"` // 0 = 4 (default)
+	AutoFetchLimit       int               `json:"autoFetchLimit,omitempty"`       // 0 = 100 (default), <0 = no limit
+	AutoFetchOnRefresh   *bool             `json:"autoFetchOnRefresh,omitempty"`   // nil = true (default)
+	CommandGitPrefix     bool              `json:"commandGitPrefix,omitempty"`     // auto-prefix "git " in command pane
+	PostPullHooks        map[string]string `json:"postPullHooks,omitempty"`        // repo path -> command run after a pull updates it
+	Editor               string            `json:"editor,omitempty"`               // "" = $EDITOR, then $VISUAL
+	GitUITool            string            `json:"gitUITool,omitempty"`            // "" = "lazygit"; "{path}" is replaced with the repo path
+	ShowTagInList        bool              `json:"showTagInList,omitempty"`        // show the nearest tag in repo descriptions
+	ShowCommitSubject    bool              `json:"showCommitSubject,omitempty"`    // show HEAD's subject line in repo descriptions
+	SimpleChangeCount    bool              `json:"simpleChangeCount,omitempty"`    // "N changed" instead of the staged/modified/untracked breakdown
+	SearchFields         []string          `json:"searchFields,omitempty"`         // fields '/' matches: "name", "path", "remote" (default all)
+	ScanHidden           bool              `json:"scanHidden,omitempty"`           // descend into dot-directories when scanning
+	ExcludePatterns      []string          `json:"excludePatterns,omitempty"`      // glob patterns (repo name or relative path) skipped when scanning
+	AutoRefreshSeconds   int               `json:"autoRefreshSeconds,omitempty"`   // 0 = off; background status refresh interval
+	BatchPullSkipDirty   *bool             `json:"batchPullSkipDirty,omitempty"`   // nil = true (default); skip repos with local changes in batch pulls
+	PullAutostash        bool              `json:"pullAutostash,omitempty"`        // pull with --rebase --autostash instead of --ff-only
+	Theme                map[string]string `json:"theme,omitempty"`                // style name -> color ("205", "#ff8800" or "light,dark")
+	KeyBindings          map[string]string `json:"keyBindings,omitempty"`          // repo list action -> key, e.g. "pull": "u"
+	PinnedRepos          []string          `json:"pinnedRepos,omitempty"`          // repo paths always listed, even outside gitDir ("~/" allowed)
+	SetupDirs            []string          `json:"setupDirs,omitempty"`            // extra git directory candidates offered by setup
+	ExpandedGroups       []string          `json:"expandedGroups,omitempty"`       // groups expanded inline on the homepage
+	ColorByActivity      bool              `json:"colorByActivity,omitempty"`      // tint repo names by HEAD commit age
 }
 
 func (c Config) GetShowPullResults() bool {
@@ -32,6 +114,106 @@ func (c Config) GetShowPullResults() bool {
 	return *c.ShowPullResults
 }
 
+func (c Config) GetAutoFetchOnRefresh() bool {
+	if c.AutoFetchOnRefresh == nil {
+		return true // default
+	}
+	return *c.AutoFetchOnRefresh
+}
+
+// GetBatchPullSkipDirty reports whether batch pulls leave dirty repos alone (default true)
+func (c Config) GetBatchPullSkipDirty() bool {
+	if c.BatchPullSkipDirty == nil {
+		return true
+	}
+	return *c.BatchPullSkipDirty
+}
+
+// GetSearchFields returns the repo fields '/' matches against (default name, path and remote)
+func (c Config) GetSearchFields() []string {
+	if len(c.SearchFields) == 0 {
+		return defaultSearchFields
+	}
+	return c.SearchFields
+}
+
+// GetEditor returns the editor command, falling back to $EDITOR then $VISUAL
+func (c Config) GetEditor() string {
+	if c.Editor != "" {
+		return c.Editor
+	}
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		return editor
+	}
+	return os.Getenv("VISUAL")
+}
+
+func (c Config) GetGitUITool() string {
+	if c.GitUITool == "" {
+		return "lazygit" // default
+	}
+	return c.GitUITool
+}
+
+// GetAutoRefreshSeconds returns the background refresh interval, 0 if disabled
+func (c Config) GetAutoRefreshSeconds() int {
+	if c.AutoRefreshSeconds < 0 {
+		return 0
+	}
+	return c.AutoRefreshSeconds
+}
+
+// GetDetailLogCount returns how many recent and incoming commits the detail
+// view lists; non-positive values fall back to the default
+func (c Config) GetDetailLogCount() int {
+	if c.DetailLogCount <= 0 {
+		return 10 // default
+	}
+	return c.DetailLogCount
+}
+
+// GetDetailStatusWidthPct returns the status pane's percentage of the detail
+// view width, clamped to 30-80 so neither pane gets unusably narrow
+func (c Config) GetDetailStatusWidthPct() int {
+	if c.DetailStatusWidthPct == 0 {
+		return 60 // default
+	}
+	return min(max(c.DetailStatusWidthPct, 30), 80)
+}
+
+// GetDetailCmdHeight returns the command pane height in lines, clamped to
+// 4-20 so the input and some output always fit
+func (c Config) GetDetailCmdHeight() int {
+	if c.DetailCmdHeight == 0 {
+		return 6 // default
+	}
+	return min(max(c.DetailCmdHeight, 4), 20)
+}
+
+// GetPinnedRepos returns the pinned repo paths with "~/" expanded
+func (c Config) GetPinnedRepos() []string {
+	paths := make([]string, 0, len(c.PinnedRepos))
+	for _, p := range c.PinnedRepos {
+		paths = append(paths, filepath.Clean(expandHome(p)))
+	}
+	return paths
+}
+
+// expandHome expands "~" or a leading "~/" to the user's home directory
+func expandHome(path string) string {
+	if path == "~" {
+		if home, err := os.UserHomeDir(); err == nil {
+			return home
+		}
+	}
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
+
 func (c Config) GetMaxCommitsPerRepo() int {
 	if c.MaxCommitsPerRepo <= 0 {
 		return 5 // default
@@ -39,6 +221,23 @@ func (c Config) GetMaxCommitsPerRepo() int {
 	return c.MaxCommitsPerRepo
 }
 
+func (c Config) GetMaxConcurrentFetches() int {
+	if c.MaxConcurrentFetches <= 0 {
+		return 4 // default
+	}
+	return c.MaxConcurrentFetches
+}
+
+// GetAutoFetchLimit returns the max repo count for startup auto-fetch, 0 if unlimited
+func (c Config) GetAutoFetchLimit() int {
+	if c.AutoFetchLimit < 0 {
+		return 0 // no limit
+	}
+	if c.AutoFetchLimit == 0 {
+		return 100 // default
+	}
+	return c.AutoFetchLimit
+}
 
 // GroupsFile represents the groups storage format
 type GroupsFile struct {
@@ -62,6 +261,14 @@ func getGroupsPath() string {
 	return filepath.Join(getConfigDir(), "groups.json")
 }
 
+func getHistoryPath() string {
+	return filepath.Join(getConfigDir(), "history.json")
+}
+
+func getLastPulledPath() string {
+	return filepath.Join(getConfigDir(), "last-pulled.json")
+}
+
 func getGotoFilePath() string {
 	return filepath.Join(getConfigDir(), ".goto")
 }
@@ -108,7 +315,7 @@ func loadFavorites() map[string]bool {
 	}
 
 	for _, path := range paths {
-		favorites[path] = true
+		favorites[absRepoPath(path)] = true
 	}
 	return favorites
 }
@@ -117,9 +324,10 @@ func saveFavorites(favorites map[string]bool) {
 	var paths []string
 	for path, isFav := range favorites {
 		if isFav {
-			paths = append(paths, path)
+			paths = append(paths, relRepoPath(path))
 		}
 	}
+	sort.Strings(paths)
 
 	data, err := json.MarshalIndent(paths, "", "  ")
 	if err != nil {
@@ -130,6 +338,271 @@ func saveFavorites(favorites map[string]bool) {
 	os.WriteFile(getFavoritesPath(), data, 0644)
 }
 
+// loadLastPulled returns when each repo was last pulled successfully
+func loadLastPulled() map[string]time.Time {
+	lastPulled := make(map[string]time.Time)
+
+	data, err := os.ReadFile(getLastPulledPath())
+	if err != nil {
+		return lastPulled
+	}
+
+	var stored map[string]time.Time
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return lastPulled
+	}
+
+	for path, t := range stored {
+		lastPulled[absRepoPath(path)] = t
+	}
+	return lastPulled
+}
+
+func saveLastPulled(lastPulled map[string]time.Time) {
+	stored := make(map[string]time.Time, len(lastPulled))
+	for path, t := range lastPulled {
+		stored[relRepoPath(path)] = t
+	}
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return
+	}
+
+	os.MkdirAll(getConfigDir(), 0755)
+	os.WriteFile(getLastPulledPath(), data, 0644)
+}
+
+// maxHistory is how many command pane entries are kept across restarts
+const maxHistory = 50
+
+func loadCommandHistory() []string {
+	var history []string
+
+	data, err := os.ReadFile(getHistoryPath())
+	if err != nil {
+		return history
+	}
+
+	if err := json.Unmarshal(data, &history); err != nil {
+		return []string{}
+	}
+	return history
+}
+
+func saveCommandHistory(history []string) {
+	if len(history) > maxHistory {
+		history = history[len(history)-maxHistory:]
+	}
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return
+	}
+
+	os.MkdirAll(getConfigDir(), 0755)
+	os.WriteFile(getHistoryPath(), data, 0644)
+}
+
+// ignoreFileName is an optional file in the git root listing patterns to skip
+const ignoreFileName = ".guppiignore"
+
+// loadExcludePatterns combines the configured exclude patterns with those
+// from a .guppiignore file in gitDir (one pattern per line, # for comments)
+func loadExcludePatterns(gitDir string, configured []string) []string {
+	patterns := append([]string{}, configured...)
+
+	data, err := os.ReadFile(filepath.Join(gitDir, ignoreFileName))
+	if err != nil {
+		return patterns
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
+
+// isExcluded reports whether a directory, given by its path relative to the
+// git root, matches any exclude pattern by full relative path or by name
+func isExcluded(relPath string, patterns []string) bool {
+	relPath = filepath.ToSlash(relPath)
+	name := filepath.Base(relPath)
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		if pattern == "" {
+			continue
+		}
+		if ok, _ := filepath.Match(pattern, relPath); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// repoBaseDir is the git directory that repo paths in favorites.json and
+// groups.json are stored relative to, so they survive moving the git directory
+var repoBaseDir string
+
+// setRepoBaseDir sets the directory stored repo paths are resolved against
+func setRepoBaseDir(dir string) {
+	repoBaseDir = filepath.Clean(dir)
+}
+
+// relRepoPath converts a repo path for storage: relative to repoBaseDir
+// when inside it, unchanged otherwise
+func relRepoPath(path string) string {
+	if repoBaseDir == "" || !filepath.IsAbs(path) {
+		return path
+	}
+	rel, err := filepath.Rel(repoBaseDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
+// absRepoPath resolves a stored repo path against repoBaseDir. Absolute
+// paths (from older versions or repos outside the git directory) are kept.
+func absRepoPath(path string) string {
+	if repoBaseDir == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(repoBaseDir, path)
+}
+
+func mapPaths(paths []string, convert func(string) string) []string {
+	if paths == nil {
+		return nil
+	}
+	out := make([]string, len(paths))
+	for i, p := range paths {
+		out[i] = convert(p)
+	}
+	return out
+}
+
+// migrateRepoPaths rewrites favorites.json and groups.json written with
+// absolute paths by older versions, storing paths inside repoBaseDir
+// relative to it
+func migrateRepoPaths() {
+	hasAbs := func(paths []string) bool {
+		for _, p := range paths {
+			if relRepoPath(p) != p {
+				return true
+			}
+		}
+		return false
+	}
+
+	var favPaths []string
+	if data, err := os.ReadFile(getFavoritesPath()); err == nil && json.Unmarshal(data, &favPaths) == nil && hasAbs(favPaths) {
+		saveFavorites(loadFavorites())
+	}
+
+	var groupsFile GroupsFile
+	if data, err := os.ReadFile(getGroupsPath()); err == nil && json.Unmarshal(data, &groupsFile) == nil {
+		for _, g := range groupsFile.Groups {
+			if hasAbs(g.Repos) || hasAbs(g.AutoRepos) {
+				saveGroups(loadGroups())
+				break
+			}
+		}
+	}
+}
+
+// ConfigBundle holds groups and favorites for --export/--import
+type ConfigBundle struct {
+	Favorites []string `json:"favorites"`
+	Groups    []Group  `json:"groups"`
+}
+
+// exportBundle writes favorites and groups to a single JSON file
+func exportBundle(file string) (ConfigBundle, error) {
+	var bundle ConfigBundle
+	for path, isFav := range loadFavorites() {
+		if isFav {
+			bundle.Favorites = append(bundle.Favorites, relRepoPath(path))
+		}
+	}
+	sort.Strings(bundle.Favorites)
+	for _, g := range loadGroups() {
+		g.Repos = mapPaths(g.Repos, relRepoPath)
+		g.AutoRepos = mapPaths(g.AutoRepos, relRepoPath)
+		bundle.Groups = append(bundle.Groups, g)
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return bundle, err
+	}
+	return bundle, os.WriteFile(file, data, 0644)
+}
+
+// importBundle replaces favorites and groups with those from a bundle file.
+// Relative paths resolve against gitDir. Returns the bundle and the repo
+// paths that don't exist under gitDir; those are still imported so they
+// resolve once the repo is cloned.
+func importBundle(file, gitDir string) (ConfigBundle, []string, error) {
+	setRepoBaseDir(gitDir)
+
+	var bundle ConfigBundle
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return bundle, nil, err
+	}
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return bundle, nil, fmt.Errorf("invalid bundle %s: %w", file, err)
+	}
+
+	var missing []string
+	seen := make(map[string]bool)
+	check := func(path string) {
+		path = absRepoPath(path)
+		if seen[path] {
+			return
+		}
+		seen[path] = true
+		rel, err := filepath.Rel(gitDir, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			missing = append(missing, path)
+			return
+		}
+		if _, err := os.Stat(path); err != nil {
+			missing = append(missing, path)
+		}
+	}
+	for _, path := range bundle.Favorites {
+		check(path)
+	}
+	for _, g := range bundle.Groups {
+		for _, path := range g.Repos {
+			check(path)
+		}
+	}
+
+	favorites := make(map[string]bool)
+	for _, path := range bundle.Favorites {
+		favorites[absRepoPath(path)] = true
+	}
+	groups := make([]Group, len(bundle.Groups))
+	for i, g := range bundle.Groups {
+		g.Repos = mapPaths(g.Repos, absRepoPath)
+		g.AutoRepos = mapPaths(g.AutoRepos, absRepoPath)
+		groups[i] = g
+	}
+	saveFavorites(favorites)
+	saveGroups(groups)
+	return bundle, missing, nil
+}
+
 func loadGroups() []Group {
 	var groupsFile GroupsFile
 
@@ -142,14 +615,20 @@ func loadGroups() []Group {
 		return []Group{}
 	}
 
+	for i := range groupsFile.Groups {
+		groupsFile.Groups[i].Repos = mapPaths(groupsFile.Groups[i].Repos, absRepoPath)
+		groupsFile.Groups[i].AutoRepos = mapPaths(groupsFile.Groups[i].AutoRepos, absRepoPath)
+	}
 	return groupsFile.Groups
 }
 
 func saveGroups(groups []Group) {
-	// Filter out built-in groups (Favorites) from saving
+	// Filter out built-in groups (Favorites, Pinned) from saving
 	var toSave []Group
 	for _, g := range groups {
 		if !g.IsBuiltIn {
+			g.Repos = mapPaths(g.Repos, relRepoPath)
+			g.AutoRepos = mapPaths(g.AutoRepos, relRepoPath)
 			toSave = append(toSave, g)
 		}
 	}
@@ -164,6 +643,34 @@ func saveGroups(groups []Group) {
 	os.WriteFile(getGroupsPath(), data, 0644)
 }
 
+// pinnedGroupName is the built-in group listing Config.PinnedRepos
+const pinnedGroupName = "Pinned"
+
+// savePinnedRepos stores the Pinned group's repos in config.json, keeping
+// entries as written (e.g. with "~/") when the set is unchanged
+func savePinnedRepos(paths []string) {
+	config := loadConfig()
+	current := config.GetPinnedRepos()
+	if len(current) == len(paths) {
+		same := true
+		inPaths := make(map[string]bool, len(paths))
+		for _, p := range paths {
+			inPaths[p] = true
+		}
+		for _, p := range current {
+			if !inPaths[p] {
+				same = false
+				break
+			}
+		}
+		if same {
+			return
+		}
+	}
+	config.PinnedRepos = paths
+	saveConfigFull(config)
+}
+
 func buildGroupsMap(groups []Group) map[string]*Group {
 	m := make(map[string]*Group)
 	for i := range groups {
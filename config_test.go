@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsExcludedLiteralName(t *testing.T) {
+	patterns := []string{"monorepo-mirror"}
+	if !isExcluded("monorepo-mirror", patterns) {
+		t.Error("expected top-level repo to be excluded")
+	}
+	if !isExcluded("work/monorepo-mirror", patterns) {
+		t.Error("expected nested repo to be excluded by name")
+	}
+	if isExcluded("monorepo", patterns) {
+		t.Error("expected non-matching repo to be kept")
+	}
+}
+
+func TestIsExcludedGlob(t *testing.T) {
+	patterns := []string{"archive-*"}
+	for _, name := range []string{"archive-2019", "archive-old", "team/archive-x"} {
+		if !isExcluded(name, patterns) {
+			t.Errorf("expected %q to be excluded", name)
+		}
+	}
+	if isExcluded("my-archive", patterns) {
+		t.Error("expected my-archive to be kept")
+	}
+}
+
+func TestIsExcludedRelativePath(t *testing.T) {
+	patterns := []string{"vendor/*", "mirrors/"}
+	if !isExcluded("vendor/lib", patterns) {
+		t.Error("expected vendor/lib to be excluded by path pattern")
+	}
+	if isExcluded("app/lib", patterns) {
+		t.Error("expected app/lib to be kept")
+	}
+	if !isExcluded("mirrors", patterns) {
+		t.Error("expected trailing slash pattern to match directory")
+	}
+}
+
+func TestIsExcludedNoPatterns(t *testing.T) {
+	if isExcluded("anything", nil) {
+		t.Error("expected nothing excluded without patterns")
+	}
+}
+
+func TestLoadExcludePatternsReadsIgnoreFile(t *testing.T) {
+	dir := t.TempDir()
+	content := "# huge repos\narchive-*\n\n  monorepo  \n"
+	if err := os.WriteFile(filepath.Join(dir, ignoreFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := loadExcludePatterns(dir, []string{"scratch"})
+	want := []string{"scratch", "archive-*", "monorepo"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("pattern %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestLoadExcludePatternsWithoutIgnoreFile(t *testing.T) {
+	got := loadExcludePatterns(t.TempDir(), nil)
+	if len(got) != 0 {
+		t.Errorf("expected no patterns, got %v", got)
+	}
+}
+
+func TestExportImportBundleRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	gitDir := t.TempDir()
+	present := filepath.Join(gitDir, "app")
+	if err := os.Mkdir(present, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// A name starting with ".." is still inside gitDir
+	dotted := filepath.Join(gitDir, "..dotfiles")
+	if err := os.Mkdir(dotted, 0755); err != nil {
+		t.Fatal(err)
+	}
+	absent := filepath.Join(gitDir, "not-cloned")
+	elsewhere := "/somewhere/else/repo"
+	setRepoBaseDir(gitDir)
+	t.Cleanup(func() { repoBaseDir = "" })
+
+	saveFavorites(map[string]bool{present: true})
+	saveGroups([]Group{{Name: "work", Repos: []string{present, dotted, absent, elsewhere}}})
+
+	file := filepath.Join(t.TempDir(), "bundle.json")
+	if _, err := exportBundle(file); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	// Start from an empty config, as on a new machine
+	saveFavorites(map[string]bool{})
+	saveGroups(nil)
+
+	bundle, missing, err := importBundle(file, gitDir)
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if len(bundle.Groups) != 1 || len(bundle.Favorites) != 1 {
+		t.Errorf("unexpected bundle: %+v", bundle)
+	}
+	if len(missing) != 2 || missing[0] != absent || missing[1] != elsewhere {
+		t.Errorf("expected %q and %q reported missing, got %v", absent, elsewhere, missing)
+	}
+
+	if !loadFavorites()[present] {
+		t.Error("expected favorite to be restored")
+	}
+	groups := loadGroups()
+	if len(groups) != 1 || len(groups[0].Repos) != 4 {
+		t.Errorf("expected group with all 4 repos (missing ones kept), got %+v", groups)
+	}
+}
+
+func TestRepoPathsStoredRelativeToGitDir(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { repoBaseDir = "" })
+
+	// Files written by older versions contain absolute paths
+	oldDir := filepath.Join(t.TempDir(), "git")
+	setRepoBaseDir(oldDir)
+	writeJSON := func(path string, v any) {
+		data, _ := json.Marshal(v)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(getConfigDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeJSON(getFavoritesPath(), []string{filepath.Join(oldDir, "app")})
+	writeJSON(getGroupsPath(), GroupsFile{Groups: []Group{{Name: "work", Repos: []string{filepath.Join(oldDir, "team", "api"), "/elsewhere/lib"}}}})
+
+	migrateRepoPaths()
+
+	var favs []string
+	data, _ := os.ReadFile(getFavoritesPath())
+	json.Unmarshal(data, &favs)
+	if len(favs) != 1 || favs[0] != "app" {
+		t.Errorf("expected migrated favorite %q, got %v", "app", favs)
+	}
+
+	// After moving the git directory, paths resolve against the new location
+	newDir := filepath.Join(t.TempDir(), "code")
+	setRepoBaseDir(newDir)
+	if !loadFavorites()[filepath.Join(newDir, "app")] {
+		t.Errorf("expected favorite under %s, got %v", newDir, loadFavorites())
+	}
+	groups := loadGroups()
+	want := []string{filepath.Join(newDir, "team", "api"), "/elsewhere/lib"}
+	if len(groups) != 1 || len(groups[0].Repos) != 2 || groups[0].Repos[0] != want[0] || groups[0].Repos[1] != want[1] {
+		t.Errorf("expected group repos %v, got %+v", want, groups)
+	}
+}
+
+func TestSetupCandidateDirs(t *testing.T) {
+	home := "/home/u"
+	env := "~/Development/source" + string(filepath.ListSeparator) + "/srv/code"
+	dirs := setupCandidateDirs(home, env, []string{"/srv/code/", "~/work"})
+
+	want := []string{"/home/u/Development/source", "/srv/code", "/home/u/work", "/home/u/git"}
+	for i, w := range want {
+		if i >= len(dirs) || dirs[i] != w {
+			t.Fatalf("expected %v first, got %v", want, dirs)
+		}
+	}
+	if len(dirs) != 9 {
+		t.Errorf("expected 3 custom + 6 built-in dirs, got %v", dirs)
+	}
+}
+
+func TestReplaceShellBlockIsIdempotent(t *testing.T) {
+	block := getShellFunction("zsh")
+	rc := "export PATH=$HOME/bin:$PATH\n"
+
+	once, _ := replaceShellBlock(rc, "zsh", block)
+	twice, _ := replaceShellBlock(once, "zsh", block)
+	if once != twice {
+		t.Errorf("second install changed the file:\n%s\n---\n%s", once, twice)
+	}
+	if strings.Count(twice, shellBlockStart) != 1 {
+		t.Errorf("expected one marked block, got:\n%s", twice)
+	}
+
+	removed, ok, err := removeShellBlock(twice, "zsh")
+	if err != nil || !ok || removed != rc {
+		t.Errorf("removeShellBlock() = %q, %v; want %q", removed, ok, rc)
+	}
+}
+
+func TestReplaceShellBlockMigratesLegacyFunction(t *testing.T) {
+	for _, shellType := range []string{"zsh", "fish"} {
+		legacy := strings.NewReplacer(shellBlockStart+"\n", "", shellBlockEnd+"\n", "").Replace(getShellFunction(shellType))
+		rc := "# before\n" + legacy + "# after\n"
+
+		got, err := replaceShellBlock(rc, shellType, getShellFunction(shellType))
+		want := "# before\n" + getShellFunction(shellType) + "# after\n"
+		if err != nil || got != want {
+			t.Errorf("%s: replaceShellBlock() =\n%s\nwant\n%s", shellType, got, want)
+		}
+	}
+}
+
+func TestRemoveShellBlockWithoutBlock(t *testing.T) {
+	rc := "alias ll='ls -l'\n"
+	if got, ok, err := removeShellBlock(rc, "bash"); err != nil || ok || got != rc {
+		t.Errorf("removeShellBlock() = %q, %v, %v; want unchanged", got, ok, err)
+	}
+}
+
+func TestShellBlockWithDanglingStartMarker(t *testing.T) {
+	// The end marker was deleted by hand; appending a new block would make
+	// the old start marker swallow everything up to the new end marker
+	rc := "# before\n" + strings.Replace(getShellFunction("zsh"), shellBlockEnd+"\n", "", 1) + "alias ll='ls -l'\n"
+
+	if got, err := replaceShellBlock(rc, "zsh", getShellFunction("zsh")); err != errDanglingShellBlock || got != rc {
+		t.Errorf("replaceShellBlock() = %q, %v; want unchanged and errDanglingShellBlock", got, err)
+	}
+	if got, ok, err := removeShellBlock(rc, "zsh"); err != errDanglingShellBlock || ok || got != rc {
+		t.Errorf("removeShellBlock() = %q, %v, %v; want unchanged and errDanglingShellBlock", got, ok, err)
+	}
+}
+
+func TestLastPulledRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	gitDir := t.TempDir()
+	setRepoBaseDir(gitDir)
+	t.Cleanup(func() { repoBaseDir = "" })
+
+	pulled := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	saveLastPulled(map[string]time.Time{filepath.Join(gitDir, "app"): pulled})
+
+	got := loadLastPulled()[filepath.Join(gitDir, "app")]
+	if !got.Equal(pulled) {
+		t.Errorf("loaded %v, want %v", got, pulled)
+	}
+}
+
+func TestDetailLayoutClamped(t *testing.T) {
+	cases := []struct {
+		config     Config
+		pct, lines int
+	}{
+		{Config{}, 60, 6},
+		{Config{DetailStatusWidthPct: 45, DetailCmdHeight: 12}, 45, 12},
+		{Config{DetailStatusWidthPct: 95, DetailCmdHeight: 1}, 80, 4},
+		{Config{DetailStatusWidthPct: -5, DetailCmdHeight: 99}, 30, 20},
+	}
+	for _, c := range cases {
+		if pct, lines := c.config.GetDetailStatusWidthPct(), c.config.GetDetailCmdHeight(); pct != c.pct || lines != c.lines {
+			t.Errorf("%+v: got %d%%, %d lines; want %d%%, %d lines", c.config, pct, lines, c.pct, c.lines)
+		}
+	}
+}
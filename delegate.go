@@ -4,22 +4,52 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
 )
 
+// activityStyle picks the tint for a repo name: recent when HEAD was
+// committed within a day, stale after a month. ok is false in between and
+// when the commit time is unknown.
+func activityStyle(lastCommit int64, now time.Time) (style lipgloss.Style, ok bool) {
+	if lastCommit == 0 {
+		return style, false
+	}
+	age := now.Sub(time.Unix(lastCommit, 0))
+	switch {
+	case age < 24*time.Hour:
+		return recentStyle, true
+	case age > 30*24*time.Hour:
+		return staleStyle, true
+	}
+	return style, false
+}
+
 // repoDelegate is a custom delegate that renders both Repo and GroupItem
 type repoDelegate struct {
 	list.DefaultDelegate
 	favorites  map[string]bool   // maps are reference types, so this shares data with model
 	repoGroups map[string]string // repo path -> group name for display when filtering
+	loading    map[string]bool   // repos with a status check in flight, shared with model
+	marked     map[string]bool   // repos selected for a bulk move, shared with model
+	frame      *string           // current spinner frame, updated by the model on each tick
+
+	colorByActivity   bool // config: tint repo names by HEAD commit age
+	showTagInList     bool // config: add the nearest tag to descriptions
+	showCommitSubject bool // config: add HEAD's subject line to descriptions
+	simpleChangeCount bool // config: "N changed" instead of the staged/modified/untracked breakdown
 }
 
-func newRepoDelegate(favorites map[string]bool) repoDelegate {
+func newRepoDelegate(favorites, loading, marked map[string]bool, frame *string) repoDelegate {
 	d := repoDelegate{
 		DefaultDelegate: list.NewDefaultDelegate(),
 		favorites:       favorites,
 		repoGroups:      make(map[string]string),
+		loading:         loading,
+		marked:          marked,
+		frame:           frame,
 	}
 	d.ShowDescription = true
 	return d
@@ -62,16 +92,30 @@ func (d repoDelegate) Render(w io.Writer, m list.Model, index int, item list.Ite
 	// Look up favorite from shared map for instant updates
 	isFavorite := d.favorites[repo.Path]
 
+	name := repo.Name
+	if d.colorByActivity && !isSelected {
+		if style, ok := activityStyle(repo.LastCommitTime, time.Now()); ok {
+			name = style.Render(name)
+		}
+	}
+
 	// Render with updated favorite state
 	var title string
 	if isFavorite {
-		title = favoriteStyle.Render("★") + " " + repo.Name
+		title = favoriteStyle.Render("★") + " " + name
 	} else {
-		title = "  " + repo.Name
+		title = "  " + name
+	}
+
+	if d.marked[repo.Path] {
+		title = successStyle.Render("✓") + title
 	}
 
-	// Show group prefix if we have one (used when filtering on homepage)
-	if groupName, hasGroup := d.repoGroups[repo.Path]; hasGroup && groupName != "" {
+	// Show group prefix if we have one (used when filtering on homepage);
+	// repos under an expanded group are indented instead
+	if repo.Nested {
+		title = "    " + title
+	} else if groupName, hasGroup := d.repoGroups[repo.Path]; hasGroup && groupName != "" {
 		title = "[" + groupName + "] " + title
 	}
 
@@ -79,7 +123,13 @@ func (d repoDelegate) Render(w io.Writer, m list.Model, index int, item list.Ite
 		title += " " + branchStyle.Render("["+repo.Branch+"]")
 	}
 
-	desc := repo.Description()
+	desc := d.description(repo)
+	if repo.Status == StatusUnknown && d.loading[repo.Path] {
+		desc = *d.frame + helpStyle.Render("checking...")
+	}
+	if repo.Nested {
+		desc = "    " + desc
+	}
 
 	if isSelected {
 		title = itemStyles.SelectedTitle.Render(title)
@@ -91,3 +141,75 @@ func (d repoDelegate) Render(w io.Writer, m list.Model, index int, item list.Ite
 
 	fmt.Fprintf(w, "%s\n%s", title, desc)
 }
+
+// description renders a repo's second line: its status followed by the
+// details the config turns on
+func (d repoDelegate) description(r Repo) string {
+	var status string
+	switch r.Status {
+	case StatusClean:
+		status = statusCleanStyle.Render("✓ clean")
+	case StatusCleanBehind:
+		status = statusDirtyStyle.Render(fmt.Sprintf("↓ %d behind%s", r.BehindCount, r.upstreamAgeSuffix()))
+	case StatusDirty:
+		if r.BehindCount > 0 {
+			status = statusDirtyStyle.Render(fmt.Sprintf("● %s | ↓ %d behind%s", d.changeText(r), r.BehindCount, r.upstreamAgeSuffix()))
+		} else {
+			status = statusDirtyStyle.Render("● " + d.changeText(r))
+		}
+	case StatusError:
+		status = statusErrorStyle.Render("✗ " + r.StatusText)
+	case StatusConflict:
+		status = statusErrorStyle.Render("✗ " + r.StatusText)
+	case StatusEmpty:
+		status = helpStyle.Render("○ (empty) no commits yet")
+		if r.StatusText != "" {
+			status += " | " + statusDirtyStyle.Render("● "+d.changeText(r))
+		}
+	default:
+		status = "..."
+	}
+
+	if r.Detached {
+		status += " | " + statusDirtyStyle.Render("⚠ detached")
+	}
+
+	if r.Operation != "" {
+		status += " | " + statusErrorStyle.Render("⚠ "+r.Operation+" in progress")
+	}
+
+	if r.LastCommit != "" {
+		status += " " + helpStyle.Render("· "+r.LastCommit)
+	}
+
+	if d.showCommitSubject && r.LastSubject != "" {
+		status += " " + helpStyle.Render("· \""+truncateRunes(r.LastSubject, 40)+"\"")
+	}
+
+	if d.showTagInList && r.LatestTag != "" {
+		status += " " + helpStyle.Render("· "+r.tagLabel())
+	}
+
+	if !r.LastPulled.IsZero() {
+		status += " " + helpStyle.Render("· pulled "+formatAge(time.Since(r.LastPulled)))
+	}
+
+	if r.StashCount > 0 {
+		status += " | " + stashStyle.Render(fmt.Sprintf("⚑ %d stashed", r.StashCount))
+	}
+
+	if r.PullResult != "" {
+		status += " | " + pullResultStyle.Render(r.PullResult)
+	}
+
+	return status
+}
+
+// changeText describes a dirty working tree, collapsed to "N changed" when
+// simpleChangeCount is set
+func (d repoDelegate) changeText(r Repo) string {
+	if d.simpleChangeCount && r.ChangedCount > 0 {
+		return fmt.Sprintf("%d changed", r.ChangedCount)
+	}
+	return r.StatusText
+}
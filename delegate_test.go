@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestDescriptionShowsCommitSubject(t *testing.T) {
+	r := Repo{Status: StatusClean, LastSubject: "Fix login bug when the session cookie has expired"}
+	if strings.Contains((repoDelegate{}).description(r), "Fix login") {
+		t.Error("subject shown without showCommitSubject")
+	}
+	if desc := (repoDelegate{showCommitSubject: true}).description(r); !strings.Contains(desc, `"Fix login bug when the session cookie ha..."`) {
+		t.Errorf("description missing truncated subject: %q", desc)
+	}
+}
+
+func TestActivityStyleByCommitAge(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cases := []struct {
+		age  time.Duration
+		want lipgloss.Style
+		ok   bool
+	}{
+		{time.Hour, recentStyle, true},
+		{7 * 24 * time.Hour, lipgloss.Style{}, false},
+		{60 * 24 * time.Hour, staleStyle, true},
+	}
+	for _, c := range cases {
+		style, ok := activityStyle(now.Add(-c.age).Unix(), now)
+		if ok != c.ok || (ok && style.GetForeground() != c.want.GetForeground()) {
+			t.Errorf("activityStyle(%v old) = %v, %v", c.age, style.GetForeground(), ok)
+		}
+	}
+	if _, ok := activityStyle(0, now); ok {
+		t.Error("unknown commit time was tinted")
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// keyHelp is a key binding and what it does
+type keyHelp struct {
+	key  string
+	desc string
+}
+
+// helpSection groups key bindings by the context they apply in
+type helpSection struct {
+	title string
+	keys  []keyHelp
+}
+
+// keyHelpSections lists every key binding, shared by the '?' overlay and --help
+var keyHelpSections = []helpSection{
+	{"Homepage", []keyHelp{
+		{"Enter", "Enter selected group / Pull selected repo"},
+		{"n", "Create new group"},
+		{"H", "Auto-group ungrouped repos by remote host (again to undo)"},
+		{"e", "Rename selected group"},
+		{"b", "Set pull strategy and fetch mode of selected group"},
+		{"K/J", "Move selected group up/down (also shift+↑/↓)"},
+		{"z", "Expand/collapse selected group inline (remembered)"},
+		{"x", "Delete selected group"},
+		{"m", "Move repo (or all selected repos) to group"},
+		{"space", "Select repo for a bulk move with m (esc clears)"},
+		{"s", "Open git UI (lazygit by default) for selected repo"},
+		{"d", "Open detail view (multi-pane)"},
+		{"E", "Open repo in editor ($EDITOR)"},
+		{"F", "Reveal repo in file manager"},
+		{"o", "Open repo in browser"},
+		{"O", "Open current branch in browser (create PR page for feature branches)"},
+		{"f", "Toggle favorite"},
+		{"ctrl+p", "Pin/unpin repo (Pinned group; pinned paths may be outside the git directory)"},
+		{"p", "Pull selected repo"},
+		{"P", "Pull all favorites"},
+		{"A", "Pull all repos behind remote"},
+		{"U", "Pull all repos (or all in current group)"},
+		{"V", "Preview pull: fetch, then show behind counts and fast-forwards before pulling"},
+		{"N", "Clone a new repo into the git directory"},
+		{"y", "Copy repo path to clipboard"},
+		{"T", "Trust a repo owned by another user (git safe.directory)"},
+		{"g", "Goto repo directory (cd)"},
+		{"1", "Filter: repos with local changes"},
+		{"2", "Filter: repos behind remote"},
+		{"3", "Filter: repos with a detached HEAD"},
+		{"4", "Filter: repos with merge conflicts"},
+		{"5", "Filter: hide clean, up-to-date repos"},
+		{"0", "Clear filters"},
+		{"t", "Cycle sort: name/status/behind/recent"},
+		{"/", "Search repos by name, path or remote URL"},
+		{"r", "Refresh (mode-aware: selected/favorites/all)"},
+		{"ctrl+r", "Full refresh (always refreshes all, drops cached pull-result files)"},
+		{"ctrl+f", "Fetch all remotes without merging (all repos, or selected/current group)"},
+		{"c", "Configure git directory"},
+		{"S", "Open settings (performance options)"},
+		{"?", "Show this help"},
+		{"q", "Quit"},
+	}},
+	{"Inside group", []keyHelp{
+		{"esc", "Back to parent group / homepage"},
+		{"n", "Create subgroup"},
+		{"Enter", "Enter subgroup"},
+		{"a", "Add repos to group"},
+		{"x", "Remove selected repo from group / delete subgroup"},
+		{"m", "Move repo to different group"},
+		{"r", "Refresh repos in current group"},
+		{"", "(other keys work same as homepage)"},
+	}},
+	{"Detail view", []keyHelp{
+		{"Tab", "Switch pane (status/branches/command)"},
+		{"Enter", "Switch branch / Run command"},
+		{"/", "Filter branches by name"},
+		{"PgUp/PgDn", "Move a page through branches (branches pane)"},
+		{"g/G", "First/last branch, also Home/End (branches pane)"},
+		{"n", "Create new branch and switch to it"},
+		{"e", "Rename selected local branch"},
+		{"p", "Pull remote branch to local"},
+		{"t", "Set the selected branch's upstream to origin/<name>"},
+		{"x", "Delete local-only branch"},
+		{"X", "Force delete local branch (asks first if it has unmerged commits)"},
+		{"D", "Delete branch on remote (with confirmation)"},
+		{"b", "Rebase the current branch onto the selected one (with confirmation)"},
+		{"↑/↓", "Select changed file (status pane)"},
+		{"space", "Stage/unstage selected file (status pane)"},
+		{"Enter", "Show diff of selected file (status pane)"},
+		{"c", "Commit staged changes (status pane)"},
+		{"r", "Refresh"},
+		{"ctrl+r", "Rescan all repos and reload this view"},
+		{"s", "Manage stashes (apply/pop/drop)"},
+		{"u", "Restore the latest auto-stash from a branch switch"},
+		{"H", "Reset --hard to upstream, discarding local commits (asks twice)"},
+		{"R", "Show reflog for current branch"},
+		{"L", "Browse the commit log: / searches messages, enter shows a commit's message and diffstat"},
+		{"z", "Measure disk usage (working tree and object store)"},
+		{"M", "Maintenance: git gc, optionally git remote prune origin (asks first)"},
+		{"A", "Abort a merge or rebase in progress (asks first)"},
+		{"ctrl+g", "Toggle auto 'git ' prefix in command pane"},
+		{"↑/↓", "Command history (command pane)"},
+		{"ctrl+c", "Cancel running command (quits if none running)"},
+		{"?", "Show this help (outside the command pane)"},
+		{"Esc", "Back to list"},
+	}},
+	{"Settings", []keyHelp{
+		{"↑/↓", "Select option"},
+		{"Enter", "Toggle option / choose fetch mode"},
+		{"←/→", "Adjust value"},
+		{"Esc", "Back to list"},
+	}},
+	{"Pull results", []keyHelp{
+		{"↑/↓", "Navigate repos"},
+		{"Enter", "Expand/collapse commits"},
+		{"PgUp/PgDn", "Move a page within the current level"},
+		{"g/G", "First/last item in the current level, also Home/End"},
+		{"e", "Expand all repos to their commits"},
+		{"c", "Collapse all to repos"},
+		{"r", "Retry the repos whose pull failed"},
+		{"Esc", "Dismiss"},
+	}},
+}
+
+// renderKeyHelp formats the key binding sections; section titles are passed
+// through styleTitle so the overlay and --help can render them differently
+func renderKeyHelp(keys keyMap, styleTitle func(string) string) string {
+	var sb strings.Builder
+	for i, section := range keyHelpSections {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(styleTitle("Key bindings (" + strings.ToLower(section.title) + "):"))
+		sb.WriteString("\n")
+		for _, k := range section.keys {
+			key := k.key
+			if section.title == "Homepage" || section.title == "Inside group" {
+				key = keys.rebind(key)
+			}
+			sb.WriteString(fmt.Sprintf("  %-9s %s\n", key, k.desc))
+		}
+	}
+	return sb.String()
+}
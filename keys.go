@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultKeyBindings maps each remappable repo list action to its key.
+// Config.KeyBindings overrides individual entries.
+var defaultKeyBindings = map[string]string{
+	"quit":           "q",
+	"back":           "esc",
+	"favorite":       "f",
+	"pin":            "ctrl+p",
+	"pull":           "p",
+	"pullFavorites":  "P",
+	"pullBehind":     "A",
+	"pullAll":        "U",
+	"pullPreview":    "V",
+	"fetch":          "ctrl+f",
+	"refresh":        "r",
+	"fullRefresh":    "ctrl+r",
+	"gitUI":          "s",
+	"details":        "d",
+	"editor":         "E",
+	"reveal":         "F",
+	"web":            "o",
+	"branchWeb":      "O",
+	"copyPath":       "y",
+	"trust":          "T",
+	"goto":           "g",
+	"clone":          "N",
+	"configure":      "c",
+	"settings":       "S",
+	"help":           "?",
+	"sort":           "t",
+	"filterDirty":    "1",
+	"filterBehind":   "2",
+	"filterDetached": "3",
+	"filterConflict": "4",
+	"hideClean":      "5",
+	"clearFilters":   "0",
+	"newGroup":       "n",
+	"renameGroup":    "e",
+	"autoGroup":      "H",
+	"groupSettings":  "b",
+	"expandGroup":    "z",
+	"addRepos":       "a",
+	"remove":         "x",
+	"moveRepo":       "m",
+}
+
+// reservedKeys are repo list keys that can't be rebound: fixed handler keys
+// and the list's own navigation and filter keys
+var reservedKeys = map[string]bool{
+	"enter": true, "ctrl+c": true, "backspace": true, " ": true,
+	"K": true, "J": true, "shift+up": true, "shift+down": true,
+	"up": true, "down": true, "k": true, "j": true, "/": true,
+}
+
+// keyMap maps repo list actions to the keys that trigger them
+type keyMap map[string]string
+
+// loadKeyBindings merges overrides into the defaults. Overrides naming an
+// unknown action, using a reserved key or clashing with another binding are
+// dropped (keeping that action's default) and reported as warnings.
+func loadKeyBindings(overrides map[string]string) (keyMap, []string) {
+	bindings := make(keyMap, len(defaultKeyBindings))
+	for action, key := range defaultKeyBindings {
+		bindings[action] = key
+	}
+
+	var warnings []string
+	actions := make([]string, 0, len(overrides))
+	for action := range overrides {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	for _, action := range actions {
+		key := overrides[action]
+		switch {
+		case bindings[action] == "":
+			warnings = append(warnings, fmt.Sprintf("unknown action %q", action))
+		case key == "":
+			warnings = append(warnings, fmt.Sprintf("%s: empty key", action))
+		case reservedKeys[key]:
+			warnings = append(warnings, fmt.Sprintf("%s: %q is reserved", action, key))
+		default:
+			bindings[action] = key
+		}
+	}
+
+	// Undo overrides that share a key with another action until none clash;
+	// undoing one can uncover a clash with the restored default
+	for {
+		byKey := make(map[string][]string)
+		for action, key := range bindings {
+			byKey[key] = append(byKey[key], action)
+		}
+		keys := make([]string, 0, len(byKey))
+		for key := range byKey {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var clashing []string
+		for _, key := range keys {
+			owners := byKey[key]
+			if len(owners) < 2 {
+				continue
+			}
+			sort.Strings(owners)
+			for _, action := range owners {
+				if key != defaultKeyBindings[action] {
+					clashing = append(clashing, action)
+				}
+			}
+			warnings = append(warnings, fmt.Sprintf("%q bound to %s", key, strings.Join(owners, " and ")))
+		}
+		if len(clashing) == 0 {
+			break
+		}
+		for _, action := range clashing {
+			bindings[action] = defaultKeyBindings[action]
+		}
+	}
+	return bindings, warnings
+}
+
+// action returns the repo list action bound to key, or key itself when no
+// action uses it (fixed keys like enter)
+func (k keyMap) action(key string) string {
+	for action, bound := range k {
+		if bound == key {
+			return action
+		}
+	}
+	return key
+}
+
+// key returns the key bound to action, for hints like "m: move"
+func (k keyMap) key(action string) string {
+	return k[action]
+}
+
+// hint fills the {action} placeholders in a key hint with the bound keys
+func (k keyMap) hint(text string) string {
+	pairs := make([]string, 0, 2*len(k))
+	for action, key := range k {
+		pairs = append(pairs, "{"+action+"}", key)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// rebind shows the key an action's default key has been rebound to, for
+// help text; keys that aren't remappable come back unchanged
+func (k keyMap) rebind(defaultKey string) string {
+	for action, key := range defaultKeyBindings {
+		if key == defaultKey {
+			return k[action]
+		}
+	}
+	return defaultKey
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadKeyBindingsRejectsConflicts(t *testing.T) {
+	bindings, warnings := loadKeyBindings(map[string]string{
+		"details": "l", // free key: applied
+		"pull":    "f", // taken by favorite: ignored
+		"quit":    "j", // list navigation: ignored
+		"bogus":   "w", // unknown action: ignored
+		"back":    "h", // free key: applied
+		"refresh": "",  // empty: ignored
+	})
+	want := map[string]string{"details": "l", "back": "h", "pull": "p", "quit": "q", "favorite": "f", "refresh": "r"}
+	for action, key := range want {
+		if bindings[action] != key {
+			t.Errorf("%s bound to %q, want %q", action, bindings[action], key)
+		}
+	}
+	if len(warnings) != 4 {
+		t.Errorf("got %d warnings, want 4: %v", len(warnings), warnings)
+	}
+
+	// A swap is not a clash
+	bindings, warnings = loadKeyBindings(map[string]string{"pull": "f", "favorite": "p"})
+	if bindings["pull"] != "f" || bindings["favorite"] != "p" || len(warnings) != 0 {
+		t.Errorf("swap got pull=%q favorite=%q, warnings %v", bindings["pull"], bindings["favorite"], warnings)
+	}
+}
+
+func TestKeyMapActionAndHint(t *testing.T) {
+	keys, _ := loadKeyBindings(map[string]string{"pull": "u"})
+	if got := keys.action("u"); got != "pull" {
+		t.Errorf("action(u) = %q, want pull", got)
+	}
+	if got := keys.action("p"); got != "p" {
+		t.Errorf("action(p) = %q, want the unbound key back", got)
+	}
+	if got := keys.hint("{pull}: pull • enter: open"); got != "u: pull • enter: open" {
+		t.Errorf("hint = %q", got)
+	}
+	if got := keys.rebind("p"); got != "u" {
+		t.Errorf("rebind(p) = %q, want u", got)
+	}
+
+	keys, err := loadKeyBindings(map[string]string{"back": "h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if help := renderKeyHelp(keys, func(s string) string { return s }); !strings.Contains(help, "  h         Back to parent group") {
+		t.Errorf("help does not show the rebound back key:\n%s", help)
+	}
+}
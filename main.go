@@ -1,14 +1,18 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 )
 
 const version = "1.5.5"
@@ -34,6 +38,13 @@ func getShellConfig() (string, string) {
 	}
 }
 
+// Markers around the block guppi writes to the shell config, so it can be
+// found again to replace or remove it without touching anything else.
+const (
+	shellBlockStart = "# >>> guppi >>>"
+	shellBlockEnd   = "# <<< guppi <<<"
+)
+
 func getShellFunction(shellType string) string {
 	gotoFile := getGotoFilePath()
 
@@ -43,6 +54,7 @@ func getShellFunction(shellType string) string {
 	switch shellType {
 	case "fish":
 		return fmt.Sprintf(`
+%s
 # guppi - git repository manager
 function guppi
   %s
@@ -55,9 +67,11 @@ function guppi
   end
 end
 alias gpi guppi
-`, binaryPath, gotoFile, gotoFile, gotoFile)
+%s
+`, shellBlockStart, binaryPath, gotoFile, gotoFile, gotoFile, shellBlockEnd)
 	default: // bash/zsh
 		return fmt.Sprintf(`
+%s
 # guppi - git repository manager
 guppi() {
   %s
@@ -71,8 +85,145 @@ guppi() {
   fi
 }
 alias gpi=guppi
-`, binaryPath, gotoFile, gotoFile, gotoFile)
+%s
+`, shellBlockStart, binaryPath, gotoFile, gotoFile, gotoFile, shellBlockEnd)
+	}
+}
+
+// hasShellFunction reports whether content contains a guppi block, either
+// marked or written by an older version without markers
+func hasShellFunction(content string) bool {
+	return strings.Contains(content, shellBlockStart) ||
+		strings.Contains(content, "guppi()") ||
+		strings.Contains(content, "function guppi")
+}
+
+// errDanglingShellBlock is returned when the start marker has no end marker,
+// e.g. after a hand edit. The file is left alone rather than guessing where
+// the block ends.
+var errDanglingShellBlock = errors.New("found " + shellBlockStart + " without " + shellBlockEnd + "; remove the guppi block by hand")
+
+// hasDanglingShellBlock reports whether content has a start marker with no
+// end marker after it
+func hasDanglingShellBlock(content string) bool {
+	start := strings.Index(content, shellBlockStart)
+	return start != -1 && !strings.Contains(content[start:], shellBlockEnd)
+}
+
+// findShellBlock locates the guppi block in content and returns its byte
+// range. The marked block is preferred; otherwise an unmarked function from
+// an older version is located by walking to the end of its body.
+func findShellBlock(content, shellType string) (int, int, bool) {
+	if start := strings.Index(content, shellBlockStart); start != -1 {
+		rel := strings.Index(content[start:], shellBlockEnd)
+		if rel == -1 {
+			return 0, 0, false
+		}
+		end := start + rel + len(shellBlockEnd)
+		if end < len(content) && content[end] == '\n' {
+			end++
+		}
+		return start, end, true
 	}
+	return findLegacyShellFunction(content, shellType)
+}
+
+func findLegacyShellFunction(content, shellType string) (int, int, bool) {
+	// Find function start - look for "guppi() {" or "function guppi"
+	var startMarker string
+	if shellType == "fish" {
+		startMarker = "function guppi"
+	} else {
+		startMarker = "guppi() {"
+	}
+
+	startIdx := strings.Index(content, startMarker)
+	if startIdx == -1 {
+		return 0, 0, false
+	}
+
+	// Include comment line before function if present
+	if startIdx > 0 {
+		beforeFunc := content[:startIdx]
+		lastNewline := strings.LastIndex(beforeFunc[:len(beforeFunc)-1], "\n")
+		prevLine := strings.TrimSpace(beforeFunc[lastNewline+1:])
+		if strings.HasPrefix(prevLine, "# guppi") {
+			startIdx = lastNewline + 1
+		}
+	}
+
+	// Find end of function block. Fish blocks nest (if/for/while ... end),
+	// so count openers rather than stopping at the first "end".
+	lines := strings.Split(content[startIdx:], "\n")
+	depth := 0
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		closed := false
+		if shellType == "fish" {
+			first := strings.Fields(trimmed)
+			if len(first) > 0 {
+				switch first[0] {
+				case "function", "if", "for", "while", "switch", "begin":
+					depth++
+				case "end":
+					depth--
+					closed = depth == 0
+				}
+			}
+		} else {
+			depth += strings.Count(line, "{") - strings.Count(line, "}")
+			closed = depth == 0 && strings.Contains(line, "}")
+		}
+		if !closed {
+			continue
+		}
+
+		endIdx := startIdx
+		for j := 0; j <= i; j++ {
+			endIdx += len(lines[j]) + 1
+		}
+		// Check for alias line after
+		if i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "alias gpi") {
+			endIdx += len(lines[i+1]) + 1
+		}
+		if endIdx > len(content) {
+			endIdx = len(content)
+		}
+		return startIdx, endIdx, true
+	}
+	return 0, 0, false
+}
+
+// replaceShellBlock installs block into content, replacing an existing guppi
+// block in place or appending it if there is none. Applying it twice gives
+// the same result.
+func replaceShellBlock(content, shellType, block string) (string, error) {
+	if hasDanglingShellBlock(content) {
+		return content, errDanglingShellBlock
+	}
+	if start, end, ok := findShellBlock(content, shellType); ok {
+		return content[:start] + strings.TrimPrefix(block, "\n") + content[end:], nil
+	}
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + block, nil
+}
+
+// removeShellBlock strips the guppi block from content, along with the blank
+// line that was written before it
+func removeShellBlock(content, shellType string) (string, bool, error) {
+	if hasDanglingShellBlock(content) {
+		return content, false, errDanglingShellBlock
+	}
+	start, end, ok := findShellBlock(content, shellType)
+	if !ok {
+		return content, false, nil
+	}
+	if strings.HasSuffix(content[:start], "\n\n") {
+		start--
+	}
+	return content[:start] + content[end:], true, nil
 }
 
 func checkShellSetup() bool {
@@ -81,7 +232,7 @@ func checkShellSetup() bool {
 	if err != nil {
 		return false
 	}
-	return strings.Contains(string(data), "guppi()")
+	return hasShellFunction(string(data))
 }
 
 // checkShellNeedsUpdate returns true if the shell function has issues needing update
@@ -92,16 +243,19 @@ func checkShellNeedsUpdate() bool {
 		return false
 	}
 	content := string(data)
-	if !strings.Contains(content, "guppi()") {
+	if !hasShellFunction(content) {
 		return false
 	}
+	// Blocks written before markers existed can't be removed cleanly
+	if !strings.Contains(content, shellBlockStart) {
+		return true
+	}
 	// Check for hardcoded paths
 	if strings.Contains(content, "/Cellar/guppi/") || strings.Contains(content, "/bin/guppi") {
 		return true
 	}
 	// Check for recursive call (guppi without "command" prefix)
-	// Look for the pattern: guppi() { followed by just "guppi" without "command"
-	if strings.Contains(content, "guppi()") && !strings.Contains(content, "command guppi") {
+	if !strings.Contains(content, "command guppi") {
 		return true
 	}
 	// Check for missing gpi alias
@@ -120,84 +274,52 @@ func updateShellFunctionInPlace() error {
 	}
 
 	content := string(data)
-	newFunc := getShellFunction(shellType)
-
-	// Find function start - look for "guppi() {" or "function guppi"
-	var startMarker string
-	if shellType == "fish" {
-		startMarker = "function guppi"
-	} else {
-		startMarker = "guppi() {"
+	newContent, err := replaceShellBlock(content, shellType, getShellFunction(shellType))
+	if err != nil {
+		return err
 	}
-
-	startIdx := strings.Index(content, startMarker)
-	if startIdx == -1 {
+	if _, _, ok := findShellBlock(content, shellType); !ok {
 		return fmt.Errorf("function not found")
 	}
+	return os.WriteFile(rcPath, []byte(newContent), 0644)
+}
 
-	// Include comment line before function if present
-	if startIdx > 0 {
-		beforeFunc := content[:startIdx]
-		lastNewline := strings.LastIndex(beforeFunc, "\n")
-		if lastNewline != -1 {
-			prevLine := strings.TrimSpace(beforeFunc[lastNewline:])
-			if strings.HasPrefix(prevLine, "# guppi") {
-				startIdx = lastNewline + 1
-			}
-		}
+// installShellFunction writes the guppi block to the shell config, replacing
+// any existing one
+func installShellFunction() error {
+	rcPath, shellType := getShellConfig()
+	data, err := os.ReadFile(rcPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
 	}
-
-	// Find end of function block
-	endIdx := startIdx
-	remaining := content[startIdx:]
-	lines := strings.Split(remaining, "\n")
-	braceCount := 0
-	foundEnd := false
-
-	for i, line := range lines {
-		trimmed := strings.TrimSpace(line)
-
-		if strings.Contains(line, "{") {
-			braceCount++
-		}
-		if strings.Contains(line, "}") {
-			braceCount--
-			if braceCount == 0 {
-				// End of function
-				endIdx = startIdx
-				for j := 0; j <= i; j++ {
-					endIdx += len(lines[j]) + 1
-				}
-				// Check for alias line after
-				if i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "alias gpi") {
-					endIdx += len(lines[i+1]) + 1
-				}
-				foundEnd = true
-				break
-			}
-		}
-		if shellType == "fish" && trimmed == "end" {
-			endIdx = startIdx
-			for j := 0; j <= i; j++ {
-				endIdx += len(lines[j]) + 1
-			}
-			if i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "alias gpi") {
-				endIdx += len(lines[i+1]) + 1
-			}
-			foundEnd = true
-			break
-		}
+	if err := os.MkdirAll(filepath.Dir(rcPath), 0755); err != nil {
+		return err
 	}
-
-	if !foundEnd {
-		return fmt.Errorf("could not find end of function")
+	newContent, err := replaceShellBlock(string(data), shellType, getShellFunction(shellType))
+	if err != nil {
+		return err
 	}
-
-	// Replace old function with new
-	newContent := content[:startIdx] + newFunc + content[endIdx:]
 	return os.WriteFile(rcPath, []byte(newContent), 0644)
 }
 
+// uninstallShellFunction removes the guppi block from the shell config.
+// It reports whether a block was found.
+func uninstallShellFunction() (bool, error) {
+	rcPath, shellType := getShellConfig()
+	data, err := os.ReadFile(rcPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	newContent, removed, err := removeShellBlock(string(data), shellType)
+	if err != nil || !removed {
+		return false, err
+	}
+	return true, os.WriteFile(rcPath, []byte(newContent), 0644)
+}
+
 func getCurrentBinaryPath() string {
 	binaryPath, err := os.Executable()
 	if err != nil {
@@ -212,9 +334,6 @@ func updateShellFunction() {
 	currentPath := getCurrentBinaryPath()
 	rcPath, _ := getShellConfig()
 
-	successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
-	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-
 	// Check if shell function needs a full update (missing alias, hardcoded paths, etc.)
 	if checkShellNeedsUpdate() {
 		fmt.Fprintln(os.Stderr, "Updating shell function...")
@@ -223,7 +342,7 @@ func updateShellFunction() {
 			fmt.Fprintln(os.Stderr, "Run 'guppi --setup' to fix manually.")
 		} else {
 			fmt.Fprintln(os.Stderr, successStyle.Render("✓ Shell function updated"))
-			fmt.Fprintf(os.Stderr, dimStyle.Render("  Run: source %s\n"), rcPath)
+			fmt.Fprintf(os.Stderr, helpStyle.Render("  Run: source %s\n"), rcPath)
 			fmt.Fprintln(os.Stderr)
 		}
 		config.BinaryPath = currentPath
@@ -267,7 +386,7 @@ func updateShellFunction() {
 		fmt.Fprintln(os.Stderr, "Run 'guppi --setup' to fix manually.")
 	} else {
 		fmt.Fprintln(os.Stderr, successStyle.Render("✓ Shell function updated"))
-		fmt.Fprintf(os.Stderr, dimStyle.Render("  Run: source %s\n"), rcPath)
+		fmt.Fprintf(os.Stderr, helpStyle.Render("  Run: source %s\n"), rcPath)
 		fmt.Fprintln(os.Stderr)
 	}
 
@@ -276,6 +395,38 @@ func updateShellFunction() {
 	saveConfigFull(config)
 }
 
+// setupCandidateDirs lists directories offered during setup: those from
+// GUPPI_DEFAULT_DIRS (a path list) and the setupDirs config first, then the
+// built-in defaults. "~/" is expanded and duplicates are dropped.
+func setupCandidateDirs(home, envDirs string, configured []string) []string {
+	var dirs []string
+	if envDirs != "" {
+		dirs = append(dirs, filepath.SplitList(envDirs)...)
+	}
+	dirs = append(dirs, configured...)
+	for _, name := range []string{"git", "repos", "projects", "code", "src", "dev"} {
+		dirs = append(dirs, filepath.Join(home, name))
+	}
+
+	seen := make(map[string]bool)
+	var result []string
+	for _, dir := range dirs {
+		dir = strings.TrimSpace(dir)
+		if dir == "" {
+			continue
+		}
+		if strings.HasPrefix(dir, "~/") {
+			dir = filepath.Join(home, dir[2:])
+		}
+		dir = filepath.Clean(dir)
+		if !seen[dir] {
+			seen[dir] = true
+			result = append(result, dir)
+		}
+	}
+	return result
+}
+
 func runFirstTimeSetup(force bool) bool {
 	config := loadConfig()
 	if config.SetupComplete && !force {
@@ -290,17 +441,12 @@ func runFirstTimeSetup(force bool) bool {
 		return true
 	}
 
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
-	successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
-	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-	featureStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
-
 	// Welcome message
 	fmt.Fprintln(os.Stderr, titleStyle.Render("Welcome to guppi! 🚀"))
 	fmt.Fprintln(os.Stderr)
 	fmt.Fprintln(os.Stderr, "A TUI for managing your git repositories.")
 	fmt.Fprintln(os.Stderr)
-	fmt.Fprintln(os.Stderr, featureStyle.Render("Features:"))
+	fmt.Fprintln(os.Stderr, branchStyle.Render("Features:"))
 	fmt.Fprintln(os.Stderr, "  • View all repos with status, branch, and remote changes")
 	fmt.Fprintln(os.Stderr, "  • Pull repos individually or all favorites at once")
 	fmt.Fprintln(os.Stderr, "  • Filter by dirty repos or repos behind remote")
@@ -308,7 +454,7 @@ func runFirstTimeSetup(force bool) bool {
 	fmt.Fprintln(os.Stderr, "  • Press 'g' to cd into a repo (requires shell setup)")
 	fmt.Fprintln(os.Stderr)
 
-	rcPath, shellType := getShellConfig()
+	rcPath, _ := getShellConfig()
 
 	// Step 1: Git directory setup
 	fmt.Fprintln(os.Stderr, titleStyle.Render("Step 1: Git Directory"))
@@ -329,15 +475,8 @@ func runFirstTimeSetup(force bool) bool {
 		optNum++
 	}
 
-	// Check common directories
-	commonDirs := []string{
-		filepath.Join(home, "git"),
-		filepath.Join(home, "repos"),
-		filepath.Join(home, "projects"),
-		filepath.Join(home, "code"),
-		filepath.Join(home, "src"),
-		filepath.Join(home, "dev"),
-	}
+	// Check user-supplied and common directories
+	commonDirs := setupCandidateDirs(home, os.Getenv("GUPPI_DEFAULT_DIRS"), config.SetupDirs)
 
 	for _, dir := range commonDirs {
 		if dir == config.GitDir {
@@ -381,11 +520,7 @@ func runFirstTimeSetup(force bool) bool {
 	if choiceNum == customOption {
 		fmt.Fprint(os.Stderr, "Enter path: ")
 		fmt.Scanln(&gitPath)
-		gitPath = strings.TrimSpace(gitPath)
-		// Expand ~ to home directory
-		if strings.HasPrefix(gitPath, "~/") {
-			gitPath = filepath.Join(home, gitPath[2:])
-		}
+		gitPath = expandHome(strings.TrimSpace(gitPath))
 		if gitPath == "" {
 			gitPath = filepath.Join(home, "git")
 		}
@@ -394,7 +529,21 @@ func runFirstTimeSetup(force bool) bool {
 	}
 
 	if _, err := os.Stat(gitPath); os.IsNotExist(err) {
-		fmt.Fprintln(os.Stderr, dimStyle.Render("Note: Directory doesn't exist yet. You can change it later with 'c' in the app."))
+		fmt.Fprintf(os.Stderr, "%s doesn't exist. Create it? [Y/n] ", gitPath)
+		var response string
+		fmt.Scanln(&response)
+		response = strings.ToLower(strings.TrimSpace(response))
+
+		if response == "" || response == "y" || response == "yes" {
+			if err := os.MkdirAll(gitPath, 0755); err != nil {
+				fmt.Fprintf(os.Stderr, "Error creating directory: %v\n", err)
+				fmt.Fprintln(os.Stderr, helpStyle.Render("You can change it later with 'c' in the app."))
+			} else {
+				fmt.Fprintln(os.Stderr, successStyle.Render("✓ Created "+gitPath))
+			}
+		} else {
+			fmt.Fprintln(os.Stderr, helpStyle.Render("Note: Directory doesn't exist yet. You can change it later with 'c' in the app."))
+		}
 	} else {
 		fmt.Fprintln(os.Stderr, successStyle.Render("✓ "+gitPath))
 	}
@@ -404,7 +553,7 @@ func runFirstTimeSetup(force bool) bool {
 	// Step 2: Shell function setup
 	fmt.Fprintln(os.Stderr, titleStyle.Render("Step 2: Shell Integration"))
 	if shellAlreadySetup && checkShellNeedsUpdate() {
-		fmt.Fprintln(os.Stderr, "Existing shell function needs updating (has hardcoded path).")
+		fmt.Fprintln(os.Stderr, "Existing shell function needs updating.")
 		fmt.Fprintf(os.Stderr, "Update in %s? [Y/n] ", rcPath)
 
 		var response string
@@ -414,10 +563,10 @@ func runFirstTimeSetup(force bool) bool {
 		if response == "" || response == "y" || response == "yes" {
 			if err := updateShellFunctionInPlace(); err != nil {
 				fmt.Fprintf(os.Stderr, "Error updating: %v\n", err)
-				fmt.Fprintln(os.Stderr, dimStyle.Render("You may need to manually update the function."))
+				fmt.Fprintln(os.Stderr, helpStyle.Render("You may need to manually update the function."))
 			} else {
 				fmt.Fprintln(os.Stderr, successStyle.Render("✓ Shell function updated"))
-				fmt.Fprintf(os.Stderr, dimStyle.Render("  Run: source %s\n"), rcPath)
+				fmt.Fprintf(os.Stderr, helpStyle.Render("  Run: source %s\n"), rcPath)
 			}
 		}
 	} else if shellAlreadySetup {
@@ -433,22 +582,15 @@ func runFirstTimeSetup(force bool) bool {
 		response = strings.ToLower(strings.TrimSpace(response))
 
 		if response == "" || response == "y" || response == "yes" {
-			shellFunc := getShellFunction(shellType)
-			f, err := os.OpenFile(rcPath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: could not open %s: %v\n", rcPath, err)
-				fmt.Fprintln(os.Stderr, dimStyle.Render("You can add it manually later."))
+			if err := installShellFunction(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: could not write %s: %v\n", rcPath, err)
+				fmt.Fprintln(os.Stderr, helpStyle.Render("You can add it manually later."))
 			} else {
-				if _, err := f.WriteString(shellFunc); err != nil {
-					fmt.Fprintf(os.Stderr, "Error writing: %v\n", err)
-				} else {
-					fmt.Fprintln(os.Stderr, successStyle.Render("✓ Shell function added"))
-					fmt.Fprintf(os.Stderr, dimStyle.Render("  Run: source %s\n"), rcPath)
-				}
-				f.Close()
+				fmt.Fprintln(os.Stderr, successStyle.Render("✓ Shell function added"))
+				fmt.Fprintf(os.Stderr, helpStyle.Render("  Run: source %s\n"), rcPath)
 			}
 		} else {
-			fmt.Fprintln(os.Stderr, dimStyle.Render("Skipped. Run 'guppi --setup' to configure later."))
+			fmt.Fprintln(os.Stderr, helpStyle.Render("Skipped. Run 'guppi --setup' to configure later."))
 		}
 	}
 	fmt.Fprintln(os.Stderr)
@@ -461,13 +603,12 @@ func runFirstTimeSetup(force bool) bool {
 	fmt.Fprintln(os.Stderr, successStyle.Render("Setup complete! Starting guppi..."))
 	fmt.Fprintln(os.Stderr)
 	fmt.Fprintln(os.Stderr, "Note: If 'guppi' doesn't work in new terminals, reload your shell:")
-	fmt.Fprintln(os.Stderr, dimStyle.Render("  source ~/.zshrc  (or ~/.bashrc)"))
+	fmt.Fprintln(os.Stderr, helpStyle.Render("  source ~/.zshrc  (or ~/.bashrc)"))
 	fmt.Fprintln(os.Stderr)
 	return true
 }
 
 func printHelp() {
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
 	fmt.Println(titleStyle.Render("guppi") + " - Git Repository Manager TUI")
 	fmt.Println()
 	fmt.Println("Usage: guppi [options]")
@@ -476,49 +617,19 @@ func printHelp() {
 	fmt.Println("  --help, -h      Show this help message")
 	fmt.Println("  --version, -v   Show version")
 	fmt.Println("  --setup         Re-run first-time setup")
+	fmt.Println("  --uninstall-shell  Remove the guppi function from your shell config")
+	fmt.Println("  --export FILE   Export groups and favorites to a JSON bundle")
+	fmt.Println("  --import FILE   Import groups and favorites from a bundle (replaces current)")
+	fmt.Println("  --status-json   Print every repo's status as JSON and exit")
+	fmt.Println("  --pull-favorites  Pull all favorite repos and exit")
+	fmt.Println("  --pull-group NAME  Pull every repo in a group (and its subgroups) and exit")
 	fmt.Println()
 	fmt.Println("Environment:")
 	fmt.Println("  GUPPI_GIT_DIR   Override git directory path")
+	fmt.Println("  EDITOR, VISUAL  Editor for 'E' (unless set in config)")
 	fmt.Println()
-	fmt.Println("Key bindings (homepage):")
-	fmt.Println("  Enter     Enter selected group / Pull selected repo")
-	fmt.Println("  n         Create new group")
-	fmt.Println("  e         Rename selected group")
-	fmt.Println("  x         Delete selected group")
-	fmt.Println("  m         Move repo to group")
-	fmt.Println("  s         Open lazygit for selected repo")
-	fmt.Println("  d         Open detail view (multi-pane)")
-	fmt.Println("  f         Toggle favorite")
-	fmt.Println("  p         Pull selected repo")
-	fmt.Println("  P         Pull all favorites")
-	fmt.Println("  A         Pull all repos behind remote")
-	fmt.Println("  g         Goto repo directory (cd)")
-	fmt.Println("  1         Filter: repos with local changes")
-	fmt.Println("  2         Filter: repos behind remote")
-	fmt.Println("  0         Clear filters")
-	fmt.Println("  /         Search repos")
-	fmt.Println("  r         Refresh (mode-aware: selected/favorites/all)")
-	fmt.Println("  ctrl+r    Full refresh (always refreshes all)")
-	fmt.Println("  c         Configure git directory")
-	fmt.Println("  S         Open settings (performance options)")
-	fmt.Println("  q         Quit")
-	fmt.Println()
-	fmt.Println("Key bindings (inside group):")
-	fmt.Println("  Esc       Return to homepage")
-	fmt.Println("  a         Add repos to group")
-	fmt.Println("  x         Remove selected repo from group")
-	fmt.Println("  m         Move repo to different group")
-	fmt.Println("  r         Refresh repos in current group")
-	fmt.Println("  (other keys work same as homepage)")
-	fmt.Println()
-	fmt.Println("Key bindings (detail view):")
-	fmt.Println("  Tab       Switch pane (status/branches/command)")
-	fmt.Println("  Enter     Switch branch / Run command")
-	fmt.Println("  p         Pull remote branch to local")
-	fmt.Println("  x         Delete local-only branch")
-	fmt.Println("  X         Force delete local branch")
-	fmt.Println("  r         Refresh")
-	fmt.Println("  Esc       Back to list")
+	keys, _ := loadKeyBindings(loadConfig().KeyBindings)
+	fmt.Print(renderKeyHelp(keys, func(s string) string { return s }))
 	fmt.Println()
 	fmt.Println("Fetch Mode Settings (press S):")
 	fmt.Println("  Fetch all       Fetch status for all repos on startup (default)")
@@ -526,7 +637,178 @@ func printHelp() {
 	fmt.Println("  Favorites only  Only fetch status for favorite repos on startup")
 }
 
+// repoStatusJSON is one repo in the --status-json output
+type repoStatusJSON struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Branch   string `json:"branch"`
+	Status   string `json:"status"` // clean, behind, dirty, conflict or error
+	Ahead    int    `json:"ahead"`
+	Behind   int    `json:"behind"`
+	Dirty    bool   `json:"dirty"`
+	Upstream bool   `json:"upstream"`
+	Error    string `json:"error,omitempty"`
+}
+
+// statusNames maps GitStatus to its --status-json name
+var statusNames = map[GitStatus]string{
+	StatusUnknown:     "unknown",
+	StatusClean:       "clean",
+	StatusCleanBehind: "behind",
+	StatusDirty:       "dirty",
+	StatusError:       "error",
+	StatusConflict:    "conflict",
+	StatusEmpty:       "empty",
+}
+
+// collectRepoStatuses scans gitDir and checks every repo's status against
+// already-fetched refs, the same way the list view does, without the TUI
+func collectRepoStatuses(gitDir string) ([]repoStatusJSON, error) {
+	found := scanForRepos(gitDir)().(repoFoundMsg)
+	if found.err != nil {
+		return nil, found.err
+	}
+
+	statuses := make([]repoStatusJSON, len(found.repos))
+	var wg sync.WaitGroup
+	for i, repo := range found.repos {
+		wg.Add(1)
+		go func(i int, repo Repo) {
+			defer wg.Done()
+			msg := checkGitStatus(repo.Path)().(statusUpdatedMsg)
+			ahead := 0
+			if msg.hasUpstream {
+				ahead, _ = aheadBehind(repo.Path, "HEAD", "@{u}")
+			}
+			statuses[i] = repoStatusJSON{
+				Name:     repo.Name,
+				Path:     repo.Path,
+				Branch:   msg.branch,
+				Status:   statusNames[msg.status],
+				Ahead:    ahead,
+				Behind:   msg.behindCount,
+				Dirty:    msg.status == StatusDirty || msg.status == StatusConflict,
+				Upstream: msg.hasUpstream,
+				Error:    msg.errDetail,
+			}
+		}(i, repo)
+	}
+	wg.Wait()
+
+	sort.Slice(statuses, func(a, b int) bool { return statuses[a].Name < statuses[b].Name })
+	return statuses, nil
+}
+
+// headlessPull pulls every path with at most maxConcurrentOps pulls running
+// at once, printing one line per repo in the order given. Group pull
+// strategies, batchPullSkipDirty and post-pull hooks apply as in the TUI.
+// It reports whether every pull and hook succeeded.
+func headlessPull(groups []Group, paths []string, config Config) bool {
+	type outcome struct {
+		skipped bool // dirty and skipped by batchPullSkipDirty
+		pull    pullCompleteMsg
+		hook    *hookResultMsg // nil when no hook ran
+	}
+	skipDirty := config.GetBatchPullSkipDirty()
+	results := make([]outcome, len(paths))
+	sem := newLimiter(maxConcurrentOps)
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			sem.Acquire()
+			defer sem.Release()
+			if skipDirty && !repoPullsDirty(groups, path, config.PullAutostash) && hasUncommittedChanges(path) {
+				results[i].skipped = true
+				return
+			}
+			results[i].pull = repoPullCmd(groups, path, config.PullAutostash)().(pullCompleteMsg)
+			if !pulledNewCommits(results[i].pull) {
+				return
+			}
+			if hook := repoPostPullHook(groups, config.PostPullHooks, path); hook != "" {
+				result := runPostPullHook(path, hook)().(hookResultMsg)
+				results[i].hook = &result
+			}
+		}(i, path)
+	}
+	wg.Wait()
+
+	lastPulled := loadLastPulled()
+	failed, skipped := 0, 0
+	for i, r := range results {
+		name := relRepoPath(paths[i])
+		if r.skipped {
+			skipped++
+			fmt.Printf("− %s: uncommitted changes\n", name)
+			continue
+		}
+		if r.pull.err != nil {
+			failed++
+			reason, _, _ := strings.Cut(r.pull.result, "\n")
+			fmt.Printf("✗ %s: %s\n", name, reason)
+			continue
+		}
+		if !r.pull.fetchOnly {
+			lastPulled[r.pull.path] = time.Now()
+		}
+		if r.hook != nil && r.hook.err != nil {
+			failed++
+			fmt.Printf("✗ %s: %s, but the post-pull hook failed: %s\n", name, r.pull.shortResult, hookSummary(r.hook.output))
+			continue
+		}
+		line := fmt.Sprintf("✓ %s: %s (%s)", name, r.pull.shortResult, formatElapsed(r.pull.elapsed))
+		if r.hook != nil {
+			line += " · hook: " + truncateRunes(hookSummary(r.hook.output), 50)
+		}
+		fmt.Println(line)
+	}
+	saveLastPulled(lastPulled)
+
+	summary := fmt.Sprintf("Pulled %d repos, %d failed", len(results)-failed-skipped, failed)
+	if skipped > 0 {
+		summary += fmt.Sprintf(", %d skipped", skipped)
+	}
+	fmt.Println(summary)
+	return failed == 0
+}
+
+// groupPullPaths returns the sorted repo paths of a group and its subgroups
+func groupPullPaths(groups []Group, name string) ([]string, bool) {
+	if _, ok := buildGroupsMap(groups)[name]; !ok {
+		return nil, false
+	}
+	var paths []string
+	for path := range groupRepoPaths(groups, name) {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, true
+}
+
+// resolveGitDir picks the git directory. Priority: ENV > config file > default
+func resolveGitDir(config Config) string {
+	gitDir := os.Getenv("GUPPI_GIT_DIR")
+	if gitDir == "" {
+		gitDir = config.GitDir
+	}
+	if gitDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not find home directory")
+			os.Exit(1)
+		}
+		gitDir = filepath.Join(home, "git")
+	}
+
+	return expandHome(gitDir)
+}
+
 func main() {
+	// The theme colors the setup and help output too
+	applyTheme(loadConfig().Theme)
+
 	// Handle flags
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
@@ -541,6 +823,90 @@ func main() {
 				os.Exit(1)
 			}
 			return
+		case "--uninstall-shell":
+			rcPath, _ := getShellConfig()
+			removed, err := uninstallShellFunction()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error updating %s: %v\n", rcPath, err)
+				os.Exit(1)
+			}
+			if !removed {
+				fmt.Printf("No guppi shell function found in %s\n", rcPath)
+				return
+			}
+			fmt.Printf("Removed guppi shell function from %s\n", rcPath)
+			fmt.Println("Open a new shell (or unset the 'guppi' function and 'gpi' alias) to finish.")
+			return
+		case "--export":
+			if len(os.Args) < 3 {
+				fmt.Fprintln(os.Stderr, "Usage: guppi --export <file>")
+				os.Exit(1)
+			}
+			setRepoBaseDir(resolveGitDir(loadConfig()))
+			bundle, err := exportBundle(os.Args[2])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Export failed:", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Exported %d groups and %d favorites to %s\n", len(bundle.Groups), len(bundle.Favorites), os.Args[2])
+			return
+		case "--import":
+			if len(os.Args) < 3 {
+				fmt.Fprintln(os.Stderr, "Usage: guppi --import <file>")
+				os.Exit(1)
+			}
+			gitDir := resolveGitDir(loadConfig())
+			bundle, missing, err := importBundle(os.Args[2], gitDir)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Import failed:", err)
+				os.Exit(1)
+			}
+			for _, path := range missing {
+				fmt.Fprintf(os.Stderr, "Warning: %s does not exist under %s\n", path, gitDir)
+			}
+			fmt.Printf("Imported %d groups and %d favorites from %s\n", len(bundle.Groups), len(bundle.Favorites), os.Args[2])
+			return
+		case "--status-json":
+			config := loadConfig()
+			fetchLimiter = newLimiter(config.GetMaxConcurrentFetches())
+			gitDir := resolveGitDir(config)
+			setRepoBaseDir(gitDir)
+			statuses, err := collectRepoStatuses(gitDir)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Scan failed:", err)
+				os.Exit(1)
+			}
+			out, _ := json.MarshalIndent(statuses, "", "  ")
+			fmt.Println(string(out))
+			return
+		case "--pull-favorites", "--pull-group":
+			if os.Args[1] == "--pull-group" && len(os.Args) < 3 {
+				fmt.Fprintln(os.Stderr, "Usage: guppi --pull-group <name>")
+				os.Exit(1)
+			}
+			config := loadConfig()
+			setRepoBaseDir(resolveGitDir(config))
+			var paths []string
+			if os.Args[1] == "--pull-favorites" {
+				for path := range loadFavorites() {
+					paths = append(paths, path)
+				}
+				sort.Strings(paths)
+			} else {
+				var ok bool
+				if paths, ok = groupPullPaths(loadGroups(), os.Args[2]); !ok {
+					fmt.Fprintf(os.Stderr, "No group named %q\n", os.Args[2])
+					os.Exit(1)
+				}
+			}
+			if len(paths) == 0 {
+				fmt.Println("Nothing to pull")
+				return
+			}
+			if !headlessPull(loadGroups(), paths, config) {
+				os.Exit(1)
+			}
+			return
 		}
 	}
 
@@ -555,26 +921,7 @@ func main() {
 	// Check if binary path changed (e.g., installed via Homebrew after local build)
 	updateShellFunction()
 
-	// Priority: ENV > config file > default
-	config := loadConfig()
-	gitDir := os.Getenv("GUPPI_GIT_DIR")
-	if gitDir == "" {
-		gitDir = config.GitDir
-	}
-	if gitDir == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error: could not find home directory")
-			os.Exit(1)
-		}
-		gitDir = filepath.Join(home, "git")
-	}
-
-	// Expand ~ in git directory
-	if strings.HasPrefix(gitDir, "~/") {
-		home, _ := os.UserHomeDir()
-		gitDir = filepath.Join(home, gitDir[2:])
-	}
+	gitDir := resolveGitDir(loadConfig())
 
 	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
 		fmt.Fprintf(os.Stderr, "Git directory not found: %s\n", gitDir)
@@ -585,7 +932,7 @@ func main() {
 	// Clean up any old goto file
 	os.Remove(getGotoFilePath())
 
-	p := tea.NewProgram(initialModel(gitDir), tea.WithAltScreen())
+	p := tea.NewProgram(initialModel(gitDir), tea.WithAltScreen(), tea.WithMouseCellMotion())
 	finalModel, err := p.Run()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Error running program:", err)
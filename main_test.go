@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCollectRepoStatuses(t *testing.T) {
+	gitDir, git := newTestRepo(t)
+	for _, name := range []string{"clean", "dirty"} {
+		git("init", "-q", "-b", "main", name)
+		git("-C", name, "commit", "-q", "--allow-empty", "-m", "base")
+	}
+	if err := os.WriteFile(filepath.Join(gitDir, "dirty", "new.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := collectRepoStatuses(gitDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("got %d repos, want 2", len(statuses))
+	}
+	if s := statuses[0]; s.Name != "clean" || s.Status != "clean" || s.Dirty || s.Branch != "main" {
+		t.Errorf("clean repo = %+v", s)
+	}
+	if s := statuses[1]; s.Name != "dirty" || s.Status != "dirty" || !s.Dirty {
+		t.Errorf("dirty repo = %+v", s)
+	}
+}
+
+func TestHeadlessPullSkipsDirtyAndRunsHooks(t *testing.T) {
+	root, git := newTestRepo(t)
+	app := filepath.Join(root, "app")
+	dirty := filepath.Join(root, "dirty")
+	git("init", "-q", "-b", "main", "upstream")
+	git("-C", "upstream", "commit", "-q", "--allow-empty", "-m", "base")
+	git("clone", "-q", "upstream", "app")
+	git("clone", "-q", "upstream", "dirty")
+	git("-C", "upstream", "commit", "-q", "--allow-empty", "-m", "new")
+	if err := os.WriteFile(filepath.Join(dirty, "wip.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := Config{PostPullHooks: map[string]string{app: "echo ran > hook.txt && echo built"}}
+	if !headlessPull(nil, []string{app, dirty}, config) {
+		t.Fatal("headlessPull reported a failure")
+	}
+	if _, err := os.Stat(filepath.Join(app, "hook.txt")); err != nil {
+		t.Errorf("post-pull hook did not run: %v", err)
+	}
+	if getHeadCommit(dirty) == getHeadCommit(app) {
+		t.Error("dirty repo was pulled despite batchPullSkipDirty")
+	}
+}
+
+func TestGroupPullPathsIncludesSubgroups(t *testing.T) {
+	groups := []Group{
+		{Name: "work", Repos: []string{"/g/b", "/g/a"}},
+		{Name: "infra", Parent: "work", Repos: []string{"/g/c", "/g/a"}},
+		{Name: "home", Repos: []string{"/g/d"}},
+	}
+	paths, ok := groupPullPaths(groups, "work")
+	if !ok || !reflect.DeepEqual(paths, []string{"/g/a", "/g/b", "/g/c"}) {
+		t.Errorf("groupPullPaths(work) = %v, %v", paths, ok)
+	}
+	if _, ok := groupPullPaths(groups, "missing"); ok {
+		t.Error("groupPullPaths(missing) reported ok")
+	}
+}
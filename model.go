@@ -1,7 +1,12 @@
 package main
 
 import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/progress"
@@ -17,6 +22,8 @@ type model struct {
 	delegate      *repoDelegate
 	repos         []Repo
 	favorites     map[string]bool
+	statusLoading map[string]bool // repos queued in a fetch batch whose status hasn't arrived
+	spinnerFrame  *string         // spinner frame shown on those rows, shared with the delegate
 	scanning      bool
 	pulling       bool
 	spinner       spinner.Model
@@ -28,22 +35,59 @@ type model struct {
 	mode          viewMode
 	previousMode  viewMode // for returning from error view
 	savedFilter   string   // saved filter text for restoring after error
+	selectedKey   string   // last selected list item (see selectionKey), restored after rebuilds
 	detailRepo    *Repo
-	detailContent string
+	detailStatus  string        // branch line of the status pane
+	detailContent string        // remaining status pane sections
+	detailFiles   []ChangedFile // changed files listed in the status pane
+	fileIndex     int           // selected file in the status pane
+	diskUsage     string        // size of the detail repo, computed on demand with z
+	diffFile      string        // file shown in the diff view
 	viewport      viewport.Model
 	dirInput      textinput.Model
 	gotoPath      string // path to cd to after exit
 
 	// Branch switching
-	branches     []BranchInfo
-	branchIndex  int
-	targetBranch string
-	actionIndex  int
-	hasChanges   bool
+	branches      []BranchInfo // visible branches (filtered by branchFilter)
+	allBranches   []BranchInfo // all branches of the detail repo
+	branchIndex   int
+	targetBranch  string
+	defaultBranch string // detail repo's default branch, "" if unknown
+	autoStashed   bool   // changes were auto-stashed for the pending branch switch
+	actionIndex   int
+	hasChanges    bool
+	pendingSwitch string          // checkout target awaiting confirmation to carry untracked files along
+	untrackedLeft int             // untracked files that would come along to pendingSwitch
+	branchInput   textinput.Model // text input for new branch name
+	branchAction  string          // "new", "rename"
+	renameFrom    string          // branch being renamed
+	remoteDelete  string          // remote branch awaiting delete confirmation (e.g. "origin/feature")
+	forceDelete   string          // unmerged local branch awaiting force-delete confirmation
+	forceLoss     int             // commits on forceDelete not merged into HEAD
+	resetConfirm  int             // 1 or 2 while confirming a hard reset to upstream (asked twice)
+	gcConfirm     bool            // confirming repo maintenance (gc, optionally remote prune)
+	rebaseOnto    string          // branch awaiting confirmation to rebase the current branch onto
+	abortOp       string          // "merge" or "rebase" awaiting confirmation to abort
+	resetUpstream string          // upstream the pending hard reset targets
+	resetLoss     string          // what the pending hard reset discards, for the prompt
+	helpReturn    viewMode        // mode to return to when closing the help overlay
+	branchFilter  textinput.Model // substring filter for the branches pane
+	cloneInput    textinput.Model // repo URL to clone into the git directory
+	commitInput   textinput.Model // commit message for staged changes
+	filteringBr   bool            // branch filter input is active
+
+	// Stash management
+	stashes      []StashInfo
+	stashIndex   int
+	stashConfirm string // pending action awaiting confirmation ("apply", "pop", "drop")
 
 	// Status filters
-	filterDirty  bool // show only repos with local changes
-	filterBehind bool // show only repos behind remote
+	filterDirty    bool // show only repos with local changes
+	filterBehind   bool // show only repos behind remote
+	filterDetached bool // show only repos with a detached HEAD
+	filterConflict bool // show only repos with merge conflicts
+	hideClean      bool // hide clean, up-to-date repos
+	sortMode       SortMode
 
 	// Detail view panes
 	detailFocus detailPane      // which pane has focus
@@ -51,11 +95,25 @@ type model struct {
 	cmdOutput   string          // command output
 	cmdViewport viewport.Model  // viewport for command output
 	cmdRunning  bool            // is a command running
+	cmdGotLines bool            // running command has produced output
+	cmdProc     *exec.Cmd       // running command process (for ctrl+c)
+	cmdKilled   bool            // running command was cancelled by the user
+	cmdHistory  []string        // previously run commands, oldest first
+	historyIdx  int             // position while browsing history (len = not browsing)
+	cmdDraft    string          // input typed before browsing history
+	cmdGitMode  bool            // auto-prefix "git " to commands
+	cmdQueue    []string        // commands run in turn after the running one succeeds
 
 	// Performance config
 	fetchMode      FetchMode // How to fetch repo status
 	settingsIndex  int       // Current selection in settings view
 	forceFullFetch bool      // Force full fetch on next scan (for ctrl+r)
+	autoFetchLimit int       // config: above this many repos, FetchAll falls back to on-demand (0 = no limit)
+	autoFetch      bool      // config: fetch from remote before computing status on refresh
+	autoRefresh    int       // config: background refresh interval in seconds, 0 = off
+	skipDirty      bool      // config: skip repos with local changes in batch pulls
+	pullAutostash  bool      // config: pull with --rebase --autostash
+	autoRefreshGen int       // bumped when the interval changes to drop stale ticks
 
 	// Groups
 	groups         []Group           // all groups including Favorites
@@ -63,42 +121,68 @@ type model struct {
 	currentGroup   *Group            // nil = homepage, non-nil = inside group
 	groupInput     textinput.Model   // text input for group name
 	groupAction    string            // "new", "rename", "delete"
-	selectedRepo   *Repo             // repo selected for move operation
+	moveRepos      []Repo            // repos selected for move operation
+	marked         map[string]bool   // repos toggled with space for a bulk move, shared with delegate
 	groupIndex     int               // selection in group picker
 	addRepoIndex   int               // selection in add repos picker
 	ungroupedRepos []Repo            // repos not in current group for picker
+	expandedGroups map[string]bool   // groups expanded inline on the homepage (z)
+	settingsGroup  string            // group whose overrides groupSettingsView edits
+	groupSetIndex  int               // selection in group settings view
 
 	// Pull results view
-	pullResults       []PullResultInfo        // results from last pull operation
-	pullResultsCursor PullResultsCursor       // cursor position in tree (level, repo, commit, file)
-	filesCache        map[string][]FileChange // cache of files per commit (key: "repoPath:commitHash")
-	pendingPulls      map[string]string       // path -> HEAD before pull (for tracking commits)
-	showPullResults   bool                    // config: show results screen
-	maxCommitsPerRepo int                     // config: max commits shown per repo
+	pullResults          []PullResultInfo        // results from last pull operation
+	pullSkipped          []SkippedRepo           // repos skipped or not updated by last batch pull
+	pullFailed           []FailedPull            // repos whose pull failed in the last batch
+	retryPaths           []string                // repos the error view's retry key pulls again
+	pullResultsCursor    PullResultsCursor       // cursor position in tree (level, repo, commit, file)
+	filesCache           map[string][]FileChange // cache of files per commit (key: "repoPath:commitHash")
+	filesLoading         map[string]bool         // commits whose files are being fetched
+	pullResultsExpandAll bool                    // show commits of every repo, not just the selected one
+	pendingPulls         map[string]string       // path -> HEAD before pull (for tracking commits)
+	lastPulled           map[string]time.Time    // path -> last successful pull, persisted
+	previewRepos         []Repo                  // repos to preview once their fetch batch completes
+	previewScope         string                  // what the pull preview covers, for its title
+	pullPreview          []PullPreviewEntry      // dry-run results shown in pullPreviewView
+	showPullResults      bool                    // config: show results screen
+	maxCommitsPerRepo    int                     // config: max commits shown per repo
+	detailStatusPct      int                     // config: status pane share of the detail view width
+	detailCmdHeight      int                     // config: command pane height in the detail view
+	postPullHooks        map[string]string       // config: repo path -> post-pull command
+	gitUITool            string                  // config: external git UI command for 's'
+	detailLogCount       int                     // config: commits shown in the detail status pane
+	searchFields         []string                // config: repo fields '/' matches
+	keys                 keyMap                  // config: repo list action -> key
+
+	// Commit log view
+	logCommits   []CommitInfo    // loaded commits, newest first
+	logIndex     int             // selected commit
+	logLoading   bool            // a page of commits is being loaded
+	logDone      bool            // every matching commit has been loaded
+	logErr       string          // git's error when the log failed to load
+	logQuery     string          // message search applied, "" for the whole history
+	logSearch    textinput.Model // search input
+	logSearching bool            // typing a search
+	logShowHash  string          // commit shown in commitShowView
 
 	// Progress tracking
 	progress      progress.Model // progress bar
 	progressTotal int            // total operations in current batch
 	progressDone  int            // completed operations
 	batchOp       string         // current batch operation type ("fetch" or "pull")
+	batchStart    time.Time      // when the current batch operation started
+	fetchFailed   int            // repos whose fetch failed in the current fetch-only batch
+	cloning       bool           // a clone is running
+	quitKey       string         // quit key pressed during an operation; pressing it again quits
 
 	// Concurrency-limited queues for batch operations
 	fetchQueue *batchQueue
 	pullQueue  *batchQueue
 }
 
-func initialModel(gitDir string) model {
-	s := spinner.New()
-	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-
-	favorites := loadFavorites()
-	config := loadConfig()
-
-	// Load groups and create Favorites as built-in group
-	groups := loadGroups()
-
-	// Create Favorites group from favorites.json
+// loadAllGroups loads groups.json and prepends the built-in Favorites and
+// Pinned groups
+func loadAllGroups(favorites map[string]bool) []Group {
 	favRepos := make([]string, 0, len(favorites))
 	for path, isFav := range favorites {
 		if isFav {
@@ -110,12 +194,64 @@ func initialModel(gitDir string) model {
 		Repos:     favRepos,
 		IsBuiltIn: true,
 	}
-	// Prepend Favorites group
-	groups = append([]Group{favGroup}, groups...)
+	pinGroup := Group{
+		Name:      pinnedGroupName,
+		Repos:     loadConfig().GetPinnedRepos(),
+		IsBuiltIn: true,
+	}
+	return append([]Group{favGroup, pinGroup}, loadGroups()...)
+}
+
+// changeGitDir switches to a new git directory, re-resolving the stored
+// favorite and group paths against it
+func (m *model) changeGitDir(dir string) {
+	m.gitDir = dir
+	setRepoBaseDir(dir)
+	migrateRepoPaths()
+
+	// Keep the map shared with the delegate, just replace its contents
+	for path := range m.favorites {
+		delete(m.favorites, path)
+	}
+	for path, isFav := range loadFavorites() {
+		m.favorites[path] = isFav
+	}
+	m.groups = loadAllGroups(m.favorites)
+	m.groupsMap = buildGroupsMap(m.groups)
+	m.currentGroup = nil
+	m.lastPulled = loadLastPulled()
+}
+
+func initialModel(gitDir string) model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(titleStyle.GetForeground())
+
+	setRepoBaseDir(gitDir)
+	migrateRepoPaths()
+	favorites := loadFavorites()
+	config := loadConfig()
+	keys, keyWarnings := loadKeyBindings(config.KeyBindings)
+
+	// Limit concurrent git fetches across all status commands
+	fetchLimiter = newLimiter(config.GetMaxConcurrentFetches())
+
+	groups := loadAllGroups(favorites)
 	groupsMap := buildGroupsMap(groups)
+	expandedGroups := make(map[string]bool)
+	for _, name := range config.ExpandedGroups {
+		expandedGroups[name] = true
+	}
 
-	// Create delegate with shared favorites map for instant updates
-	delegate := newRepoDelegate(favorites)
+	// Create delegate with shared favorites and loading state for instant updates
+	statusLoading := make(map[string]bool)
+	spinnerFrame := new(string)
+	marked := make(map[string]bool)
+	delegate := newRepoDelegate(favorites, statusLoading, marked, spinnerFrame)
+	delegate.colorByActivity = config.ColorByActivity
+	delegate.showTagInList = config.ShowTagInList
+	delegate.showCommitSubject = config.ShowCommitSubject
+	delegate.simpleChangeCount = config.SimpleChangeCount
 
 	l := list.New([]list.Item{}, delegate, 0, 0)
 	l.Title = "guppi - Git Repository Manager"
@@ -137,6 +273,10 @@ func initialModel(gitDir string) model {
 	cmdInput.Placeholder = "Enter command (e.g., git log --oneline -5)..."
 	cmdInput.CharLimit = 512
 	cmdInput.Width = 60
+	if config.CommandGitPrefix {
+		cmdInput.Prompt = "> git "
+		cmdInput.Placeholder = "Enter git command (e.g., log --oneline -5, !ls for non-git)..."
+	}
 
 	// Group name input
 	groupInput := textinput.New()
@@ -144,17 +284,57 @@ func initialModel(gitDir string) model {
 	groupInput.CharLimit = 50
 	groupInput.Width = 40
 
+	// Branch name input
+	branchInput := textinput.New()
+	branchInput.Placeholder = "Enter branch name..."
+	branchInput.CharLimit = 100
+	branchInput.Width = 40
+
+	commitInput := textinput.New()
+	commitInput.Placeholder = "Commit message..."
+	commitInput.CharLimit = 500
+	commitInput.Width = 60
+
+	cloneInput := textinput.New()
+	cloneInput.Placeholder = "git@github.com:user/repo.git"
+	cloneInput.CharLimit = 500
+	cloneInput.Width = 60
+
+	branchFilter := textinput.New()
+	branchFilter.Prompt = "/ "
+	branchFilter.Placeholder = "filter branches"
+	branchFilter.CharLimit = 100
+	branchFilter.Width = 20
+
+	logSearch := textinput.New()
+	logSearch.Prompt = "/ "
+	logSearch.Placeholder = "search commit messages"
+	logSearch.CharLimit = 100
+	logSearch.Width = 40
+
 	cmdVp := viewport.New(80, 10)
 
+	history := loadCommandHistory()
+
 	// Progress bar
 	prog := progress.New(progress.WithDefaultGradient())
 	prog.Width = 30
 
+	// Surface rejected key bindings until the next error replaces them
+	var keyWarning string
+	if len(keyWarnings) > 0 {
+		keyWarning = "Key bindings ignored: " + strings.Join(keyWarnings, "; ")
+	}
+
 	return model{
 		list:              l,
 		delegate:          &delegate,
 		repos:             []Repo{},
 		favorites:         favorites,
+		expandedGroups:    expandedGroups,
+		statusLoading:     statusLoading,
+		marked:            marked,
+		spinnerFrame:      spinnerFrame,
 		scanning:          true,
 		spinner:           s,
 		gitDir:            gitDir,
@@ -163,14 +343,43 @@ func initialModel(gitDir string) model {
 		dirInput:          ti,
 		cmdInput:          cmdInput,
 		cmdViewport:       cmdVp,
+		cmdHistory:        history,
+		historyIdx:        len(history),
 		fetchMode:         config.FetchMode,
+		sortMode:          config.SortMode,
+		filterDirty:       config.FilterDirty,
+		filterBehind:      config.FilterBehind,
+		filterDetached:    config.FilterDetached,
+		filterConflict:    config.FilterConflict,
+		hideClean:         config.HideClean,
 		groups:            groups,
 		groupsMap:         groupsMap,
 		groupInput:        groupInput,
+		branchInput:       branchInput,
+		branchFilter:      branchFilter,
+		logSearch:         logSearch,
+		cloneInput:        cloneInput,
+		commitInput:       commitInput,
 		pendingPulls:      make(map[string]string),
+		lastPulled:        loadLastPulled(),
 		filesCache:        make(map[string][]FileChange),
+		filesLoading:      make(map[string]bool),
 		showPullResults:   config.GetShowPullResults(),
 		maxCommitsPerRepo: config.GetMaxCommitsPerRepo(),
+		detailStatusPct:   config.GetDetailStatusWidthPct(),
+		detailCmdHeight:   config.GetDetailCmdHeight(),
+		autoFetchLimit:    config.GetAutoFetchLimit(),
+		autoFetch:         config.GetAutoFetchOnRefresh(),
+		autoRefresh:       config.GetAutoRefreshSeconds(),
+		skipDirty:         config.GetBatchPullSkipDirty(),
+		pullAutostash:     config.PullAutostash,
+		cmdGitMode:        config.CommandGitPrefix,
+		postPullHooks:     config.PostPullHooks,
+		gitUITool:         config.GetGitUITool(),
+		detailLogCount:    config.GetDetailLogCount(),
+		searchFields:      config.GetSearchFields(),
+		keys:              keys,
+		errorMsg:          keyWarning,
 		progress:          prog,
 	}
 }
@@ -179,9 +388,103 @@ func (m model) Init() tea.Cmd {
 	return tea.Batch(
 		m.spinner.Tick,
 		scanForRepos(m.gitDir),
+		scheduleAutoRefresh(m.autoRefresh, m.autoRefreshGen),
 	)
 }
 
+// autoRefreshPresets are the intervals offered in settings (seconds, 0 = off)
+var autoRefreshPresets = []int{0, 30, 60, 120, 300, 600}
+
+// scheduleAutoRefresh returns a tick that fires after the given interval,
+// or nil if auto-refresh is disabled
+func scheduleAutoRefresh(seconds, gen int) tea.Cmd {
+	if seconds <= 0 {
+		return nil
+	}
+	return tea.Tick(time.Duration(seconds)*time.Second, func(time.Time) tea.Msg {
+		return autoRefreshMsg{gen: gen}
+	})
+}
+
+// setAutoRefresh steps the auto-refresh interval through the presets,
+// saves it, and reschedules the tick (stale ticks are dropped by generation)
+func (m *model) setAutoRefresh(step int) tea.Cmd {
+	next := m.autoRefresh
+	if step > 0 {
+		for _, p := range autoRefreshPresets {
+			if p > m.autoRefresh {
+				next = p
+				break
+			}
+		}
+	} else {
+		for i := len(autoRefreshPresets) - 1; i >= 0; i-- {
+			if autoRefreshPresets[i] < m.autoRefresh {
+				next = autoRefreshPresets[i]
+				break
+			}
+		}
+	}
+	if next == m.autoRefresh {
+		return nil
+	}
+
+	m.autoRefresh = next
+	m.autoRefreshGen++
+	config := loadConfig()
+	config.AutoRefreshSeconds = next
+	saveConfigFull(config)
+
+	if next == 0 {
+		m.statusMsg = "Auto-refresh disabled"
+	} else {
+		m.statusMsg = fmt.Sprintf("Auto-refresh every %ds", next)
+	}
+	return scheduleAutoRefresh(m.autoRefresh, m.autoRefreshGen)
+}
+
+// autoRefreshBusy reports whether a background refresh would get in the way:
+// while scanning, during batch operations or pulls, or while typing.
+func (m *model) autoRefreshBusy() bool {
+	if m.scanning || m.batchOp != "" || len(m.pendingPulls) > 0 {
+		return true
+	}
+	if m.list.FilterState() == list.Filtering {
+		return true
+	}
+	switch m.mode {
+	case listView:
+		return false
+	case detailView:
+		return m.detailFocus == paneCommand || m.filteringBr
+	}
+	return true
+}
+
+// autoRefreshPaths returns the visible repos to refresh, respecting the fetch mode
+func (m *model) autoRefreshPaths() []string {
+	fetchMode := m.effectiveFetchMode()
+	if fetchMode == FetchOnDemand {
+		if item, ok := m.list.SelectedItem().(Repo); ok {
+			return []string{item.Path}
+		}
+		return nil
+	}
+
+	var paths []string
+	for _, item := range m.list.VisibleItems() {
+		repo, ok := item.(Repo)
+		if !ok {
+			continue
+		}
+		if fetchMode == FetchFavorites && !repo.IsFavorite {
+			continue
+		}
+		paths = append(paths, repo.Path)
+	}
+	return paths
+}
+
 // Helper methods for model
 
 func (m *model) updateRepoFavorites() {
@@ -193,7 +496,47 @@ func (m *model) updateRepoFavorites() {
 
 // getRepoGroup returns the group name for a repo, empty if ungrouped
 func (m *model) getRepoGroup(path string) string {
-	for _, g := range m.groups {
+	return repoGroupName(m.groups, path)
+}
+
+// getPostPullHook returns the command to run after pulling a repo
+func (m *model) getPostPullHook(path string) string {
+	return repoPostPullHook(m.groups, m.postPullHooks, path)
+}
+
+// pullStrategy returns the pull strategy the repo's group overrides the
+// global setting with, "" if none
+func (m *model) pullStrategy(path string) string {
+	return repoPullStrategy(m.groups, path)
+}
+
+// pullsDirty reports whether pulling a repo copes with local changes
+func (m *model) pullsDirty(path string) bool {
+	return repoPullsDirty(m.groups, path, m.pullAutostash)
+}
+
+// effectivePullStrategy is how pullCmd pulls a repo, with the global
+// autostash setting resolved for repos whose groups set no strategy
+func (m *model) effectivePullStrategy(path string) string {
+	if strategy := m.pullStrategy(path); strategy != "" {
+		return strategy
+	}
+	if m.pullAutostash {
+		return PullRebase
+	}
+	return PullFFOnly
+}
+
+// pullCmd pulls a repo the way its group says, falling back to the global
+// autostash setting
+func (m *model) pullCmd(path string) tea.Cmd {
+	return repoPullCmd(m.groups, path, m.pullAutostash)
+}
+
+// repoGroupName returns the name of the first group listing a repo, empty
+// if ungrouped
+func repoGroupName(groups []Group, path string) string {
+	for _, g := range groups {
 		for _, r := range g.Repos {
 			if r == path {
 				return g.Name
@@ -203,13 +546,453 @@ func (m *model) getRepoGroup(path string) string {
 	return ""
 }
 
+// repoPostPullHook returns the command to run after pulling a repo.
+// A per-repo hook takes precedence over the repo's group hook.
+func repoPostPullHook(groups []Group, hooks map[string]string, path string) string {
+	if hook := hooks[path]; hook != "" {
+		return hook
+	}
+	if g, ok := buildGroupsMap(groups)[repoGroupName(groups, path)]; ok {
+		return g.PostPullHook
+	}
+	return ""
+}
+
+// repoGroupOverride walks from the user group a repo is in up through its
+// parents, returning the first group for which has reports an override
+func repoGroupOverride(groups []Group, path string, has func(*Group) bool) *Group {
+	var start *Group
+	for i := range groups {
+		if groups[i].IsBuiltIn {
+			continue
+		}
+		for _, p := range groups[i].Repos {
+			if p == path {
+				start = &groups[i]
+				break
+			}
+		}
+		if start != nil {
+			break
+		}
+	}
+	return groupOrAncestor(buildGroupsMap(groups), start, has)
+}
+
+// groupOrAncestor returns g or its nearest ancestor for which has is true
+func groupOrAncestor(groupsMap map[string]*Group, g *Group, has func(*Group) bool) *Group {
+	seen := make(map[string]bool)
+	for g != nil && !seen[g.Name] {
+		if has(g) {
+			return g
+		}
+		seen[g.Name] = true
+		g = groupsMap[g.Parent]
+	}
+	return nil
+}
+
+// repoPullStrategy returns the pull strategy the repo's group overrides the
+// global setting with, "" if none
+func repoPullStrategy(groups []Group, path string) string {
+	if g := repoGroupOverride(groups, path, func(g *Group) bool { return g.PullStrategy != "" }); g != nil {
+		return g.PullStrategy
+	}
+	return ""
+}
+
+// repoPullsDirty reports whether pulling a repo copes with local changes: it
+// autostashes (by its group's strategy or globally) or only fetches
+func repoPullsDirty(groups []Group, path string, autostash bool) bool {
+	switch repoPullStrategy(groups, path) {
+	case PullRebase, PullFetchOnly:
+		return true
+	case PullFFOnly:
+		return false
+	}
+	return autostash
+}
+
+// repoPullCmd pulls a repo the way its group says, falling back to the
+// global autostash setting
+func repoPullCmd(groups []Group, path string, autostash bool) tea.Cmd {
+	switch repoPullStrategy(groups, path) {
+	case PullFetchOnly:
+		return fetchInsteadOfPull(path)
+	case PullRebase:
+		return pullRepo(path, true)
+	case PullFFOnly:
+		return pullRepo(path, false)
+	}
+	return pullRepo(path, autostash)
+}
+
+// effectiveFetchMode is the fetch mode for refreshes: the current group's
+// override (or its nearest ancestor's), else the global mode
+func (m *model) effectiveFetchMode() FetchMode {
+	if g := groupOrAncestor(m.groupsMap, m.currentGroup, func(g *Group) bool { return g.FetchMode != nil }); g != nil {
+		return *g.FetchMode
+	}
+	return m.fetchMode
+}
+
+// pullStrategyLabel describes a group pull strategy, spelling out what the
+// global setting currently is for ""
+func (m *model) pullStrategyLabel(strategy string) string {
+	switch strategy {
+	case PullFFOnly:
+		return "fast-forward only"
+	case PullRebase:
+		return "rebase --autostash"
+	case PullFetchOnly:
+		return "fetch only"
+	}
+	if m.pullAutostash {
+		return "global (rebase --autostash)"
+	}
+	return "global (fast-forward only)"
+}
+
+// fetchModeLabel describes a group fetch mode override, nil meaning global
+func (m *model) fetchModeLabel(mode *FetchMode) string {
+	if mode == nil {
+		return "global (" + fetchModeName(m.fetchMode) + ")"
+	}
+	return fetchModeName(*mode)
+}
+
+// cycleGroupSetting steps the selected override of a group through its
+// values and saves the groups
+func (m *model) cycleGroupSetting(g *Group, step int) {
+	if m.groupSetIndex == 0 {
+		i := 0
+		for j, s := range pullStrategies {
+			if s == g.PullStrategy {
+				i = j
+				break
+			}
+		}
+		i = (i + step + len(pullStrategies)) % len(pullStrategies)
+		g.PullStrategy = pullStrategies[i]
+		m.statusMsg = "Pull strategy for " + g.Name + ": " + m.pullStrategyLabel(g.PullStrategy)
+	} else {
+		// nil (global) followed by each fetch mode
+		i := 0
+		if g.FetchMode != nil {
+			i = int(*g.FetchMode) + 1
+		}
+		i = (i + step + 4) % 4
+		g.FetchMode = nil
+		if i > 0 {
+			mode := FetchMode(i - 1)
+			g.FetchMode = &mode
+		}
+		m.statusMsg = "Fetch mode for " + g.Name + ": " + m.fetchModeLabel(g.FetchMode)
+	}
+	saveGroups(m.groups)
+}
+
+// rescanAll rescans the git directory and re-checks every repo with a full
+// fetch. Cached pull-result files are dropped and an open detail view
+// reloads its status and branches, so nothing shows stale state afterwards.
+func (m *model) rescanAll() tea.Cmd {
+	m.scanning = true
+	m.repos = []Repo{}
+	m.forceFullFetch = true
+	if m.list.FilterState() == list.FilterApplied {
+		m.savedFilter = m.list.FilterValue()
+	}
+	m.rememberSelection()
+	m.list.SetItems([]list.Item{})
+	m.filesCache = make(map[string][]FileChange)
+	m.statusMsg = "Scanning all..."
+
+	cmds := []tea.Cmd{m.spinner.Tick, scanForRepos(m.gitDir)}
+	if m.mode == detailView && m.detailRepo != nil {
+		cmds = append(cmds, loadGitDetail(m.detailRepo.Path, m.detailLogCount), loadBranches(m.detailRepo.Path))
+	}
+	return tea.Batch(cmds...)
+}
+
+// reloadCommitLog starts the commit log over, showing commits whose message
+// contains query (all commits when it's empty)
+func (m *model) reloadCommitLog(query string) tea.Cmd {
+	m.logQuery = query
+	m.logCommits = nil
+	m.logIndex = 0
+	m.logDone = false
+	m.logErr = ""
+	m.logLoading = true
+	return loadCommitLog(m.detailRepo.Path, query, 0)
+}
+
+// loadMoreCommits fetches the next page once the selection nears the end of
+// the loaded commits
+func (m *model) loadMoreCommits() tea.Cmd {
+	if m.logLoading || m.logDone || m.detailRepo == nil || m.logIndex < len(m.logCommits)-10 {
+		return nil
+	}
+	m.logLoading = true
+	return loadCommitLog(m.detailRepo.Path, m.logQuery, len(m.logCommits))
+}
+
+// commitLogHeight is how many commits the commit log shows at once
+func (m model) commitLogHeight() int {
+	return max(m.height-8, 5)
+}
+
+// startCommands runs commands one after another in the command pane,
+// streaming their output; a failing command stops the rest
+func (m *model) startCommands(commands []string) tea.Cmd {
+	m.cmdQueue = commands[1:]
+	m.cmdRunning = true
+	m.cmdGotLines = false
+	m.cmdKilled = false
+	m.cmdOutput = "Running: " + commands[0] + "\n\n"
+	m.cmdViewport.SetContent(m.cmdOutput)
+	return runCommand(m.detailRepo.Path, commands[0])
+}
+
+// toggleGroupExpanded expands or collapses a group inline on the homepage and
+// saves the expanded groups to the config
+func (m *model) toggleGroupExpanded(name string) {
+	if m.expandedGroups[name] {
+		delete(m.expandedGroups, name)
+	} else {
+		m.expandedGroups[name] = true
+	}
+	m.saveExpandedGroups()
+}
+
+// saveExpandedGroups writes the expanded groups to the config, sorted
+func (m *model) saveExpandedGroups() {
+	names := make([]string, 0, len(m.expandedGroups))
+	for name := range m.expandedGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	config := loadConfig()
+	config.ExpandedGroups = names
+	saveConfigFull(config)
+}
+
+// markedRepos returns the repos toggled for a bulk move, in scan order
+func (m *model) markedRepos() []Repo {
+	var repos []Repo
+	for _, r := range m.repos {
+		if m.marked[r.Path] {
+			repos = append(repos, r)
+		}
+	}
+	return repos
+}
+
+// moveReposToGroup takes repos out of every group and adds them to
+// m.groups[target], or only removes them when target is past the end.
+// Favorites follow membership of the Favorites group.
+func (m *model) moveReposToGroup(repos []Repo, target int) {
+	moving := make(map[string]bool)
+	for _, r := range repos {
+		moving[r.Path] = true
+	}
+	pinned := -1
+	for i := range m.groups {
+		newRepos := make([]string, 0)
+		for _, p := range m.groups[i].Repos {
+			if !moving[p] {
+				newRepos = append(newRepos, p)
+			}
+		}
+		if m.groups[i].Name == pinnedGroupName && (len(newRepos) != len(m.groups[i].Repos) || i == target) {
+			pinned = i
+		}
+		m.groups[i].Repos = newRepos
+	}
+
+	what := repos[0].Name
+	if len(repos) > 1 {
+		what = fmt.Sprintf("%d repos", len(repos))
+	}
+	inFavorites := false
+	if target < len(m.groups) {
+		targetGroup := &m.groups[target]
+		for _, r := range repos {
+			targetGroup.Repos = append(targetGroup.Repos, r.Path)
+		}
+		inFavorites = targetGroup.Name == "Favorites"
+		m.statusMsg = "Moved " + what + " to " + targetGroup.Name
+	} else {
+		m.statusMsg = "Removed " + what + " from group"
+	}
+
+	favoritesChanged := false
+	for i := range m.repos {
+		if moving[m.repos[i].Path] && m.favorites[m.repos[i].Path] != inFavorites {
+			m.favorites[m.repos[i].Path] = inFavorites
+			m.repos[i].IsFavorite = inFavorites
+			favoritesChanged = true
+		}
+	}
+	if favoritesChanged {
+		saveFavorites(m.favorites)
+	}
+	if pinned >= 0 {
+		savePinnedRepos(m.groups[pinned].Repos)
+	}
+
+	saveGroups(m.groups)
+	m.groupsMap = buildGroupsMap(m.groups)
+}
+
 // isRepoInGroup checks if a repo is in any group
 func (m *model) isRepoInGroup(path string) bool {
 	return m.getRepoGroup(path) != ""
 }
 
-// getGroupRepos returns all repos that belong to a specific group
+// getGroupRepos returns all repos that belong to a group or its subgroups
 func (m *model) getGroupRepos(groupName string) []Repo {
+	if _, ok := m.groupsMap[groupName]; !ok {
+		return nil
+	}
+	repoSet := m.groupRepoSet(groupName)
+	var result []Repo
+	for _, repo := range m.repos {
+		if repoSet[repo.Path] {
+			result = append(result, repo)
+		}
+	}
+	return result
+}
+
+// groupRepoSet collects the repo paths of a group and all its subgroups
+func (m *model) groupRepoSet(groupName string) map[string]bool {
+	return groupRepoPaths(m.groups, groupName)
+}
+
+// subgroups returns the direct child groups of a group, sorted by name
+func (m *model) subgroups(parent string) []Group {
+	return childGroups(m.groups, parent)
+}
+
+// groupRepoPaths collects the repo paths of a group and all its subgroups
+func groupRepoPaths(groups []Group, groupName string) map[string]bool {
+	groupsMap := buildGroupsMap(groups)
+	repoSet := make(map[string]bool)
+	seen := make(map[string]bool)
+	var walk func(name string)
+	walk = func(name string) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		if g, ok := groupsMap[name]; ok {
+			for _, path := range g.Repos {
+				repoSet[path] = true
+			}
+		}
+		for _, child := range childGroups(groups, name) {
+			walk(child.Name)
+		}
+	}
+	walk(groupName)
+	return repoSet
+}
+
+// childGroups returns the direct child groups of a group, sorted by name
+func childGroups(groups []Group, parent string) []Group {
+	var children []Group
+	for _, g := range groups {
+		if g.Parent == parent && !g.IsBuiltIn {
+			children = append(children, g)
+		}
+	}
+	sortGroups(children)
+	return children
+}
+
+// sortGroups orders sibling groups: Favorites first, then the other built-in
+// groups, then custom groups by their manual order, with unordered groups
+// following alphabetically
+func sortGroups(groups []Group) {
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.Name == "Favorites" || b.Name == "Favorites" {
+			return a.Name == "Favorites" && b.Name != "Favorites"
+		}
+		if a.IsBuiltIn != b.IsBuiltIn {
+			return a.IsBuiltIn
+		}
+		if (a.Order == 0) != (b.Order == 0) {
+			return a.Order != 0
+		}
+		if a.Order != b.Order {
+			return a.Order < b.Order
+		}
+		return a.Name < b.Name
+	})
+}
+
+// moveGroup moves a custom group up (delta < 0) or down among its sibling
+// groups, numbering all siblings so the order sticks. It reports whether
+// the group moved.
+func (m *model) moveGroup(name string, delta int) bool {
+	g, ok := m.groupsMap[name]
+	if !ok || g.IsBuiltIn {
+		return false
+	}
+	var siblings []Group
+	if m.isTopLevelGroup(*g) {
+		for _, other := range m.groups {
+			if !other.IsBuiltIn && m.isTopLevelGroup(other) {
+				siblings = append(siblings, other)
+			}
+		}
+		sortGroups(siblings)
+	} else {
+		siblings = m.subgroups(g.Parent)
+	}
+
+	idx := -1
+	for i, s := range siblings {
+		if s.Name == name {
+			idx = i
+		}
+	}
+	target := idx + delta
+	if idx < 0 || target < 0 || target >= len(siblings) {
+		return false
+	}
+	siblings[idx], siblings[target] = siblings[target], siblings[idx]
+	for i, s := range siblings {
+		m.groupsMap[s.Name].Order = i + 1
+	}
+	saveGroups(m.groups)
+	return true
+}
+
+// isTopLevelGroup reports whether a group belongs on the homepage. Groups
+// whose parent no longer exists are shown there too.
+func (m *model) isTopLevelGroup(g Group) bool {
+	if g.Parent == "" {
+		return true
+	}
+	_, ok := m.groupsMap[g.Parent]
+	return !ok
+}
+
+// groupPath renders a group's breadcrumb, e.g. "work › frontend"
+func (m *model) groupPath(name string) string {
+	parts := []string{name}
+	seen := map[string]bool{name: true}
+	for g, ok := m.groupsMap[name]; ok && g.Parent != "" && !seen[g.Parent]; g, ok = m.groupsMap[g.Parent] {
+		parts = append([]string{g.Parent}, parts...)
+		seen[g.Parent] = true
+	}
+	return strings.Join(parts, " › ")
+}
+
+// getOwnGroupRepos returns the repos directly in a group, excluding subgroups
+func (m *model) getOwnGroupRepos(groupName string) []Repo {
 	group, ok := m.groupsMap[groupName]
 	if !ok {
 		return nil
@@ -227,6 +1010,93 @@ func (m *model) getGroupRepos(groupName string) []Repo {
 	return result
 }
 
+// hasAutoGroups reports whether any repos were assigned by auto-grouping
+func (m *model) hasAutoGroups() bool {
+	for _, g := range m.groups {
+		if len(g.AutoRepos) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// autoGroupByHost assigns repos that are in no user group to a group named
+// after their origin host, creating groups as needed. Returns how many repos
+// were assigned.
+func (m *model) autoGroupByHost(hosts map[string]string) int {
+	inUserGroup := make(map[string]bool)
+	for _, g := range m.groups {
+		if g.IsBuiltIn {
+			continue
+		}
+		for _, path := range g.Repos {
+			inUserGroup[path] = true
+		}
+	}
+
+	assigned := 0
+	for _, repo := range m.repos {
+		host := hosts[repo.Path]
+		if host == "" || inUserGroup[repo.Path] {
+			continue
+		}
+		idx := -1
+		for i := range m.groups {
+			if m.groups[i].Name == host && !m.groups[i].IsBuiltIn {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			m.groups = append(m.groups, Group{Name: host, Repos: []string{}, Auto: true})
+			idx = len(m.groups) - 1
+		}
+		m.groups[idx].Repos = append(m.groups[idx].Repos, repo.Path)
+		m.groups[idx].AutoRepos = append(m.groups[idx].AutoRepos, repo.Path)
+		assigned++
+	}
+
+	if assigned > 0 {
+		m.groupsMap = buildGroupsMap(m.groups)
+		saveGroups(m.groups)
+	}
+	return assigned
+}
+
+// undoAutoGroups removes repos assigned by auto-grouping and deletes auto
+// created groups left empty. Returns how many repos were ungrouped.
+func (m *model) undoAutoGroups() int {
+	removed := 0
+	var kept []Group
+	for _, g := range m.groups {
+		if len(g.AutoRepos) > 0 {
+			auto := make(map[string]bool)
+			for _, path := range g.AutoRepos {
+				auto[path] = true
+			}
+			var repos []string
+			for _, path := range g.Repos {
+				if auto[path] {
+					removed++
+					continue
+				}
+				repos = append(repos, path)
+			}
+			g.Repos = repos
+			g.AutoRepos = nil
+		}
+		if g.Auto && len(g.Repos) == 0 {
+			continue
+		}
+		kept = append(kept, g)
+	}
+
+	m.groups = kept
+	m.groupsMap = buildGroupsMap(m.groups)
+	saveGroups(m.groups)
+	return removed
+}
+
 // getUngroupedRepos returns repos not in any group
 func (m *model) getUngroupedRepos() []Repo {
 	grouped := make(map[string]bool)
@@ -247,14 +1117,11 @@ func (m *model) getUngroupedRepos() []Repo {
 // buildGroupStats builds GroupItem with stats from repos
 func (m *model) buildGroupStats(group Group) GroupItem {
 	item := GroupItem{Name: group.Name}
-	repoSet := make(map[string]bool)
-	for _, path := range group.Repos {
-		repoSet[path] = true
-	}
+	repoSet := m.groupRepoSet(group.Name)
 	for _, repo := range m.repos {
 		if repoSet[repo.Path] {
 			item.RepoCount++
-			if repo.Status == StatusDirty {
+			if repo.hasLocalChanges() {
 				item.DirtyCount++
 			}
 			if repo.BehindCount > 0 {
@@ -265,7 +1132,94 @@ func (m *model) buildGroupStats(group Group) GroupItem {
 	return item
 }
 
+// statusRank orders statuses for SortByStatus: dirty first, clean last
+func statusRank(s GitStatus) int {
+	switch s {
+	case StatusConflict:
+		return 0
+	case StatusDirty:
+		return 1
+	case StatusCleanBehind:
+		return 2
+	case StatusError:
+		return 3
+	case StatusClean, StatusEmpty:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// sortRepos orders repos by sort mode, optionally keeping favorites first.
+// Name is the final tie-breaker so the order is stable across refreshes.
+func sortRepos(repos []Repo, mode SortMode, favoritesFirst bool) {
+	sort.Slice(repos, func(i, j int) bool {
+		a, b := repos[i], repos[j]
+		if favoritesFirst && a.IsFavorite != b.IsFavorite {
+			return a.IsFavorite
+		}
+		switch mode {
+		case SortByStatus:
+			if ra, rb := statusRank(a.Status), statusRank(b.Status); ra != rb {
+				return ra < rb
+			}
+		case SortByBehind:
+			if a.BehindCount != b.BehindCount {
+				return a.BehindCount > b.BehindCount
+			}
+		case SortByRecent:
+			if a.LastCommitTime != b.LastCommitTime {
+				return a.LastCommitTime > b.LastCommitTime
+			}
+		}
+		return a.Name < b.Name
+	})
+}
+
+// selectionKey identifies a list item across list rebuilds
+func selectionKey(item list.Item) string {
+	switch it := item.(type) {
+	case Repo:
+		return "repo:" + it.Path
+	case GroupItem:
+		return "group:" + it.Name
+	}
+	return ""
+}
+
+// rememberSelection records the selected item so rebuilding the list (or a
+// rescan that empties it) keeps the cursor on it
+func (m *model) rememberSelection() {
+	if key := selectionKey(m.list.SelectedItem()); key != "" {
+		m.selectedKey = key
+	}
+}
+
+// restoreSelection moves the cursor back to the remembered item if it's
+// still visible
+func (m *model) restoreSelection() {
+	if m.selectedKey == "" {
+		return
+	}
+	for i, item := range m.list.VisibleItems() {
+		if selectionKey(item) == m.selectedKey {
+			m.list.Select(i)
+			return
+		}
+	}
+}
+
+// reapplyFilter re-applies filter text after a list rebuild, which otherwise
+// resets the cursor to the top
+func (m *model) reapplyFilter(text string) {
+	m.list.SetFilterText(text)
+	m.restoreSelection()
+}
+
 func (m *model) updateList() {
+	m.rememberSelection()
+	defer m.restoreSelection()
+
 	// Update delegate's repoGroups map for display
 	m.delegate.repoGroups = make(map[string]string)
 	for _, g := range m.groups {
@@ -275,31 +1229,30 @@ func (m *model) updateList() {
 	}
 	m.list.SetDelegate(*m.delegate)
 
-	// If inside a group, show only that group's repos
+	// If inside a group, show its subgroups and its own repos
 	if m.currentGroup != nil {
-		repos := m.getGroupRepos(m.currentGroup.Name)
-		sort.Slice(repos, func(i, j int) bool {
-			return repos[i].Name < repos[j].Name
-		})
+		repos := m.getOwnGroupRepos(m.currentGroup.Name)
+		sortRepos(repos, m.sortMode, false)
+
+		var items []list.Item
+		for _, g := range m.subgroups(m.currentGroup.Name) {
+			items = append(items, m.buildGroupStats(g))
+		}
 
 		// Apply status filters
 		var filtered []Repo
 		for _, repo := range repos {
-			if m.filterDirty && repo.Status != StatusDirty {
-				continue
-			}
-			if m.filterBehind && repo.BehindCount == 0 {
+			if !m.passesFilters(repo) {
 				continue
 			}
 			filtered = append(filtered, repo)
 		}
 
-		items := make([]list.Item, len(filtered))
-		for i, repo := range filtered {
-			items[i] = repo
+		for _, repo := range filtered {
+			items = append(items, m.repoItem(repo))
 		}
 		m.list.SetItems(items)
-		m.list.Title = "📁 " + m.currentGroup.Name
+		m.list.Title = "📁 " + m.groupPath(m.currentGroup.Name)
 		return
 	}
 
@@ -308,49 +1261,48 @@ func (m *model) updateList() {
 
 	var items []list.Item
 
-	// Add groups (Favorites first, then alphabetically)
+	// Add groups (Favorites first, then by manual order and name)
 	var sortedGroups []Group
 	for _, g := range m.groups {
+		// Subgroups are shown inside their parent
+		if !m.isTopLevelGroup(g) {
+			continue
+		}
 		// Only show groups with repos
 		stats := m.buildGroupStats(g)
 		if stats.RepoCount > 0 || !g.IsBuiltIn {
 			sortedGroups = append(sortedGroups, g)
 		}
 	}
-	sort.Slice(sortedGroups, func(i, j int) bool {
-		// Favorites always first
-		if sortedGroups[i].Name == "Favorites" {
-			return true
-		}
-		if sortedGroups[j].Name == "Favorites" {
-			return false
-		}
-		return sortedGroups[i].Name < sortedGroups[j].Name
-	})
+	sortGroups(sortedGroups)
 
 	for _, g := range sortedGroups {
 		stats := m.buildGroupStats(g)
 		items = append(items, stats)
+		if !m.expandedGroups[g.Name] {
+			continue
+		}
+		// Expanded groups list their repos (subgroups included) beneath them
+		repos := m.getGroupRepos(g.Name)
+		sortRepos(repos, m.sortMode, false)
+		for _, repo := range repos {
+			if m.passesFilters(repo) {
+				repo.Nested = true
+				items = append(items, m.repoItem(repo))
+			}
+		}
 	}
 
 	// Add ungrouped repos
 	ungrouped := m.getUngroupedRepos()
-	sort.Slice(ungrouped, func(i, j int) bool {
-		if ungrouped[i].IsFavorite != ungrouped[j].IsFavorite {
-			return ungrouped[i].IsFavorite
-		}
-		return ungrouped[i].Name < ungrouped[j].Name
-	})
+	sortRepos(ungrouped, m.sortMode, true)
 
 	// Apply status filters to ungrouped repos
 	for _, repo := range ungrouped {
-		if m.filterDirty && repo.Status != StatusDirty {
+		if !m.passesFilters(repo) {
 			continue
 		}
-		if m.filterBehind && repo.BehindCount == 0 {
-			continue
-		}
-		items = append(items, repo)
+		items = append(items, m.repoItem(repo))
 	}
 
 	m.list.SetItems(items)
@@ -358,6 +1310,9 @@ func (m *model) updateList() {
 
 // updateListFlattened shows all repos in a flat list with group prefixes (used during filtering on homepage)
 func (m *model) updateListFlattened() {
+	m.rememberSelection()
+	defer m.restoreSelection()
+
 	// Update delegate's repoGroups map for display
 	m.delegate.repoGroups = make(map[string]string)
 	for _, g := range m.groups {
@@ -367,23 +1322,15 @@ func (m *model) updateListFlattened() {
 	}
 	m.list.SetDelegate(*m.delegate)
 
-	// Sort all repos: favorites first, then alphabetically
+	// Sort all repos: favorites first, then by sort mode
 	allRepos := make([]Repo, len(m.repos))
 	copy(allRepos, m.repos)
-	sort.Slice(allRepos, func(i, j int) bool {
-		if allRepos[i].IsFavorite != allRepos[j].IsFavorite {
-			return allRepos[i].IsFavorite
-		}
-		return allRepos[i].Name < allRepos[j].Name
-	})
+	sortRepos(allRepos, m.sortMode, true)
 
 	// Apply status filters
 	var filtered []Repo
 	for _, repo := range allRepos {
-		if m.filterDirty && repo.Status != StatusDirty {
-			continue
-		}
-		if m.filterBehind && repo.BehindCount == 0 {
+		if !m.passesFilters(repo) {
 			continue
 		}
 		filtered = append(filtered, repo)
@@ -391,19 +1338,39 @@ func (m *model) updateListFlattened() {
 
 	items := make([]list.Item, len(filtered))
 	for i, repo := range filtered {
-		items[i] = repo
+		items[i] = m.repoItem(repo)
 	}
 	m.list.SetItems(items)
 }
 
+// repoItem prepares a repo for the list with the text '/' matches
+func (m *model) repoItem(repo Repo) list.Item {
+	repo.filterText = repoSearchText(repo, m.searchFields)
+	return repo
+}
+
+// passesFilters reports whether a repo matches every active status filter
+func (m *model) passesFilters(repo Repo) bool {
+	switch {
+	case m.filterDirty && !repo.hasLocalChanges():
+		return false
+	case m.filterBehind && repo.BehindCount == 0:
+		return false
+	case m.filterDetached && !repo.Detached:
+		return false
+	case m.filterConflict && repo.Status != StatusConflict:
+		return false
+	case m.hideClean && repo.Status == StatusClean:
+		return false
+	}
+	return true
+}
+
 // getFilteredRepos returns repos matching current status filters
 func (m *model) getFilteredRepos() []Repo {
 	var filtered []Repo
 	for _, repo := range m.repos {
-		if m.filterDirty && repo.Status != StatusDirty {
-			continue
-		}
-		if m.filterBehind && repo.BehindCount == 0 {
+		if !m.passesFilters(repo) {
 			continue
 		}
 		filtered = append(filtered, repo)
@@ -411,28 +1378,288 @@ func (m *model) getFilteredRepos() []Repo {
 	return filtered
 }
 
+// statusPaneContent renders the status pane: the branch line, the changed
+// files with the selected one highlighted, and the remaining detail sections
+func (m *model) statusPaneContent() string {
+	statusErr := ""
+	if m.detailRepo != nil && m.detailRepo.StatusErr != "" {
+		statusErr = statusErrorStyle.Render("--- git status failed ---\n"+m.detailRepo.StatusErr) + "\n"
+	}
+	if m.detailStatus == "" && len(m.detailFiles) == 0 {
+		return statusErr + m.detailContent
+	}
+
+	var sb strings.Builder
+	sb.WriteString("--- Status ---\n")
+	sb.WriteString(m.detailStatus)
+	if m.detailRepo != nil && m.detailRepo.LatestTag != "" {
+		sb.WriteString("  " + helpStyle.Render("tag "+m.detailRepo.tagLabel()))
+	}
+	if m.diskUsage != "" {
+		sb.WriteString("  " + helpStyle.Render(m.diskUsage))
+	}
+	if m.detailRepo != nil && m.detailRepo.Operation != "" {
+		sb.WriteString("  " + statusErrorStyle.Render("⚠ "+m.detailRepo.Operation+" in progress (A: abort)"))
+	}
+	sb.WriteString("\n")
+	for i, f := range m.detailFiles {
+		prefix := "  "
+		style := statusDirtyStyle
+		switch {
+		case f.Untracked():
+			style = helpStyle
+		case f.Staged() && !f.Unstaged():
+			style = statusCleanStyle
+		}
+		if i == m.fileIndex && m.detailFocus == paneStatus {
+			prefix = "> "
+			style = style.Bold(true)
+		}
+		sb.WriteString(prefix + style.Render(string([]byte{f.Index, f.Worktree})+" "+f.Path) + "\n")
+	}
+	sb.WriteString(statusErr)
+	sb.WriteString(m.detailContent)
+	return sb.String()
+}
+
+// scrollToFile keeps the selected file visible in the status pane viewport.
+// File lines start after the "--- Status ---" header and branch line.
+func (m *model) scrollToFile() {
+	line := m.fileIndex + 2
+	if line < m.viewport.YOffset {
+		m.viewport.SetYOffset(line)
+	} else if line >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(line - m.viewport.Height + 1)
+	}
+}
+
+// saveFilters persists the status filters so they survive restarts
+func (m *model) saveFilters() {
+	config := loadConfig()
+	config.FilterDirty = m.filterDirty
+	config.FilterBehind = m.filterBehind
+	config.FilterDetached = m.filterDetached
+	config.FilterConflict = m.filterConflict
+	config.HideClean = m.hideClean
+	saveConfigFull(config)
+}
+
+// openHelp shows the key binding overlay, remembering the current mode
+func (m *model) openHelp() {
+	m.helpReturn = m.mode
+	m.mode = helpView
+	m.viewport.SetContent(renderKeyHelp(m.keys, func(s string) string { return branchStyle.Render(s) }))
+	m.viewport.GotoTop()
+}
+
+// applyBranchFilter narrows m.branches to the branches whose name contains
+// the filter text, keeping the selected branch selected when it still matches.
+func (m *model) applyBranchFilter() {
+	selected := ""
+	if m.branchIndex >= 0 && m.branchIndex < len(m.branches) {
+		selected = m.branches[m.branchIndex].Name
+	}
+
+	query := strings.ToLower(strings.TrimSpace(m.branchFilter.Value()))
+	if query == "" {
+		m.branches = m.allBranches
+	} else {
+		m.branches = nil
+		for _, b := range m.allBranches {
+			if strings.Contains(strings.ToLower(b.Name), query) {
+				m.branches = append(m.branches, b)
+			}
+		}
+	}
+
+	m.branchIndex = 0
+	for i, b := range m.branches {
+		if b.Name == selected {
+			m.branchIndex = i
+			break
+		}
+	}
+}
+
+// resetBranchFilter clears the branch filter, e.g. when leaving detail view.
+func (m *model) resetBranchFilter() {
+	m.filteringBr = false
+	m.branchFilter.SetValue("")
+	m.branchFilter.Blur()
+}
+
+// refreshRepo returns the command to refresh a repo's status, fetching
+// from the remote first unless auto-fetch on refresh is disabled.
+func (m *model) refreshRepo(path string) tea.Cmd {
+	if m.fetchingOnly() {
+		return fetchAllRemotes(path)
+	}
+	if m.autoFetch {
+		return fetchRepo(path)
+	}
+	return checkGitStatus(path)
+}
+
+// fetchingOnly reports whether the current fetch batch is an explicit
+// fetch --all --prune rather than a status refresh
+func (m model) fetchingOnly() bool {
+	return m.fetchQueue != nil && m.fetchQueue.fetchOnly
+}
+
 // startFetchBatch starts a concurrency-limited batch fetch operation.
 // Returns the tea.Cmds to kick off the first batch.
 func (m *model) startFetchBatch(paths []string, statusMessage string) []tea.Cmd {
+	return m.startBatch(paths, statusMessage, false)
+}
+
+// startBatch starts a fetch batch; fetchOnly batches fetch all remotes
+// without merging instead of refreshing
+func (m *model) startBatch(paths []string, statusMessage string, fetchOnly bool) []tea.Cmd {
 	if len(paths) == 0 {
 		return nil
 	}
 	q := newBatchQueue(paths, maxConcurrentOps)
+	q.fetchOnly = fetchOnly
 	m.fetchQueue = &q
+	for _, p := range paths {
+		m.statusLoading[p] = true
+	}
 	m.batchOp = "fetch"
+	m.batchStart = time.Now()
 	m.progressTotal = len(paths)
 	m.progressDone = 0
 	m.statusMsg = statusMessage
 
 	initial := q.Start()
-	cmds := make([]tea.Cmd, 0, len(initial)+1)
+	cmds := make([]tea.Cmd, 0, len(initial)+2)
 	for _, p := range initial {
-		cmds = append(cmds, checkGitStatus(p))
+		cmds = append(cmds, m.refreshRepo(p))
+	}
+	cmds = append(cmds, m.spinner.Tick, m.progress.SetPercent(0))
+	return cmds
+}
+
+// operationInProgress reports whether quitting now would interrupt work
+func (m model) operationInProgress() bool {
+	return m.pulling || m.batchOp != "" || m.cmdRunning || m.cloning
+}
+
+// confirmQuit arms a quit while an operation is in flight, returning true if
+// the caller should wait for the same key again instead of quitting
+func (m *model) confirmQuit(armed bool, key string) bool {
+	if armed || !m.operationInProgress() {
+		return false
+	}
+	m.quitKey = key
+	m.statusMsg = "Operations in progress, press " + key + " again to quit"
+	return true
+}
+
+// startFetchOnlyBatch fetches all remotes for the given repos without
+// merging, then refreshes their status
+func (m *model) startFetchOnlyBatch(paths []string, statusMessage string) []tea.Cmd {
+	m.fetchFailed = 0
+	return m.startBatch(paths, statusMessage, true)
+}
+
+// startPullPreview fetches the given repos, then shows what pulling them
+// would do without merging anything
+func (m *model) startPullPreview(repos []Repo, scope string) []tea.Cmd {
+	paths := make([]string, len(repos))
+	for i, repo := range repos {
+		paths[i] = repo.Path
+	}
+	cmds := m.startFetchOnlyBatch(paths, fmt.Sprintf("Fetching %d repos to preview pull (%s)...", len(paths), scope))
+	if len(cmds) > 0 {
+		m.previewRepos = repos
+		m.previewScope = scope
+	}
+	return cmds
+}
+
+// openPullPreview shows the loaded pull preview
+func (m *model) openPullPreview() {
+	m.mode = pullPreviewView
+	m.statusMsg = ""
+	m.viewport.SetContent(renderPullPreview(m.pullPreview, m.previewSkipsDirty))
+	m.viewport.GotoTop()
+}
+
+// previewSkipsDirty reports whether pulling from the preview will leave the
+// entry out for its local changes, as skipDirtyRepos does
+func (m *model) previewSkipsDirty(entry PullPreviewEntry) bool {
+	return m.skipDirty && entry.Dirty && !m.pullsDirty(entry.RepoPath)
+}
+
+// pullFromPreview pulls the previewed repos that can be pulled, recording
+// the rest as skipped for the pull results screen
+func (m *model) pullFromPreview() []tea.Cmd {
+	m.pullResults = nil
+	m.pullSkipped = nil
+	m.pendingPulls = make(map[string]string)
+
+	byPath := make(map[string]Repo, len(m.repos))
+	for _, repo := range m.repos {
+		byPath[repo.Path] = repo
+	}
+	var toPull []Repo
+	for _, entry := range m.pullPreview {
+		if reason := entry.Reason(); reason != "" {
+			m.pullSkipped = append(m.pullSkipped, SkippedRepo{RepoName: entry.RepoName, Reason: reason})
+			continue
+		}
+		if repo, ok := byPath[entry.RepoPath]; ok {
+			toPull = append(toPull, repo)
+		}
+	}
+	toPull, dirty := m.skipDirtyRepos(toPull)
+	m.pullPreview = nil
+	cmds := m.startPullBatch(toPull, fmt.Sprintf("Pulling %d repos (%s)%s...", len(toPull), m.previewScope, dirtySkippedSuffix(dirty)))
+	if len(cmds) == 0 {
+		m.pullSkipped = nil
+		m.statusMsg = "Nothing to pull" + dirtySkippedSuffix(dirty)
 	}
-	cmds = append(cmds, m.progress.SetPercent(0))
 	return cmds
 }
 
+// skipDirtyRepos filters out repos with local changes, recording them as
+// skipped for the pull results screen. Returns the clean repos and skip count.
+// Does nothing when batch pulls are configured to include dirty repos, and
+// keeps repos whose pull autostashes local changes anyway or only fetches.
+func (m *model) skipDirtyRepos(repos []Repo) ([]Repo, int) {
+	if !m.skipDirty {
+		return repos, 0
+	}
+	var clean []Repo
+	skipped := 0
+	for _, repo := range repos {
+		if m.pullsDirty(repo.Path) {
+			clean = append(clean, repo)
+			continue
+		}
+		dirty := repo.hasLocalChanges()
+		if repo.Status == StatusUnknown || repo.Status == StatusError {
+			// Status not known yet, ask git directly
+			dirty = hasUncommittedChanges(repo.Path)
+		}
+		if dirty {
+			m.pullSkipped = append(m.pullSkipped, SkippedRepo{RepoName: repo.Name, Reason: "uncommitted changes"})
+			skipped++
+			continue
+		}
+		clean = append(clean, repo)
+	}
+	return clean, skipped
+}
+
+// dirtySkippedSuffix describes skipped dirty repos for batch pull status lines
+func dirtySkippedSuffix(skipped int) string {
+	if skipped == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", skipped %d dirty", skipped)
+}
+
 // startPullBatch starts a concurrency-limited batch pull operation.
 // Returns the tea.Cmds to kick off the first batch.
 func (m *model) startPullBatch(repos []Repo, statusMessage string) []tea.Cmd {
@@ -446,8 +1673,10 @@ func (m *model) startPullBatch(repos []Repo, statusMessage string) []tea.Cmd {
 	}
 	q := newBatchQueue(paths, maxConcurrentOps)
 	m.pullQueue = &q
+	m.pullFailed = nil
 	m.pulling = true
 	m.batchOp = "pull"
+	m.batchStart = time.Now()
 	m.progressTotal = len(paths)
 	m.progressDone = 0
 	m.statusMsg = statusMessage
@@ -455,12 +1684,145 @@ func (m *model) startPullBatch(repos []Repo, statusMessage string) []tea.Cmd {
 	initial := q.Start()
 	cmds := make([]tea.Cmd, 0, len(initial)+2)
 	for _, p := range initial {
-		cmds = append(cmds, pullRepo(p))
+		cmds = append(cmds, m.pullCmd(p))
 	}
 	cmds = append(cmds, m.spinner.Tick, m.progress.SetPercent(0))
 	return cmds
 }
 
+// retryPulls pulls the given repos again: one repo as a single pull, several
+// as a batch
+func (m *model) retryPulls(paths []string) []tea.Cmd {
+	m.pullResults = nil
+	m.pullSkipped = nil
+	m.pullFailed = nil
+	if len(paths) == 1 {
+		m.pulling = true
+		m.pendingPulls[paths[0]] = getHeadCommit(paths[0])
+		name := filepath.Base(paths[0])
+		for _, repo := range m.repos {
+			if repo.Path == paths[0] {
+				name = repo.Name
+				break
+			}
+		}
+		m.statusMsg = "Retrying pull of " + name + "..."
+		return []tea.Cmd{m.spinner.Tick, m.pullCmd(paths[0])}
+	}
+
+	want := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		want[path] = true
+	}
+	var repos []Repo
+	for _, repo := range m.repos {
+		if want[repo.Path] {
+			repos = append(repos, repo)
+		}
+	}
+	m.pendingPulls = make(map[string]string)
+	return m.startPullBatch(repos, fmt.Sprintf("Retrying %d failed pulls...", len(repos)))
+}
+
+// retryablePulls returns the paths of the last batch's failed pulls that can
+// be pulled again. Conflicted autostashes already pulled and are left out.
+func (m model) retryablePulls() []string {
+	var paths []string
+	for _, f := range m.pullFailed {
+		if !f.Conflicted {
+			paths = append(paths, f.RepoPath)
+		}
+	}
+	return paths
+}
+
+// showPullFailures opens the error view listing a batch's failed pulls, with
+// retry offered for those that can be pulled again
+func (m *model) showPullFailures() {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d pulls failed:\n", len(m.pullFailed))
+	for _, f := range m.pullFailed {
+		sb.WriteString("\n" + f.RepoName + ": " + f.Err)
+	}
+	m.retryPaths = m.retryablePulls()
+	m.showError(sb.String())
+	m.previousMode = listView
+}
+
+// showError opens the error view with msg. Dismissing it returns to the
+// view that was open, or to the list with its filter restored.
+func (m *model) showError(msg string) {
+	m.errorMsg = msg
+	if m.mode != errorView {
+		m.previousMode = m.mode
+	}
+	if m.list.FilterState() == list.FilterApplied {
+		m.savedFilter = m.list.FilterValue()
+	}
+	m.mode = errorView
+	m.viewport.SetContent(wrapText(m.errorMsg, m.viewport.Width))
+}
+
+// dismissError leaves the error view for the view it interrupted
+func (m *model) dismissError() tea.Cmd {
+	m.errorMsg = ""
+	m.retryPaths = nil
+	if m.previousMode == detailView && m.detailRepo != nil {
+		m.mode = detailView
+		return loadGitDetail(m.detailRepo.Path, m.detailLogCount)
+	}
+	m.mode = listView
+	m.detailRepo = nil
+	if m.savedFilter != "" {
+		m.reapplyFilter(m.savedFilter)
+		m.savedFilter = ""
+	}
+	return nil
+}
+
+// detailPaneHeight is the number of content lines in each detail view pane
+func (m model) detailPaneHeight() int {
+	// A command pane taller than the default takes its lines from the top panes
+	height := (m.height - 12 - max(m.detailCmdHeight-6, 0)) / 2
+	if height < 5 {
+		height = 5
+	}
+	return height
+}
+
+// branchPageSize is how many branches the branches pane shows at once
+func (m model) branchPageSize() int {
+	if m.filteringBr || m.branchFilter.Value() != "" {
+		return m.detailPaneHeight() - 1
+	}
+	return m.detailPaneHeight()
+}
+
+// jumpIndex applies a page or home/end key to an index into a list of count
+// items, keeping it in bounds
+func jumpIndex(key string, index, count, pageSize int) int {
+	if pageSize < 1 {
+		pageSize = 1
+	}
+	switch key {
+	case "pgup":
+		index -= pageSize
+	case "pgdown":
+		index += pageSize
+	case "home", "g":
+		index = 0
+	case "end", "G":
+		index = count - 1
+	}
+	if index >= count {
+		index = count - 1
+	}
+	if index < 0 {
+		index = 0
+	}
+	return index
+}
+
 // getPullResultsMaxItems returns the number of items at the current cursor level
 func (m *model) getPullResultsMaxItems() int {
 	switch m.pullResultsCursor.Level {
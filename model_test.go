@@ -0,0 +1,112 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJumpIndex(t *testing.T) {
+	cases := []struct {
+		key          string
+		index, count int
+		want         int
+	}{
+		{"pgdown", 0, 30, 10},
+		{"pgdown", 25, 30, 29},
+		{"pgup", 15, 30, 5},
+		{"pgup", 3, 30, 0},
+		{"home", 12, 30, 0},
+		{"G", 12, 30, 29},
+		{"end", 0, 0, 0},
+	}
+	for _, c := range cases {
+		if got := jumpIndex(c.key, c.index, c.count, 10); got != c.want {
+			t.Errorf("jumpIndex(%q, %d, %d) = %d, want %d", c.key, c.index, c.count, got, c.want)
+		}
+	}
+}
+
+func TestGroupPullStrategyInheritance(t *testing.T) {
+	groups := []Group{
+		{Name: "work", PullStrategy: PullRebase, Repos: []string{"/g/a"}},
+		{Name: "infra", Parent: "work", Repos: []string{"/g/b"}},
+		{Name: "docs", Parent: "work", PullStrategy: PullFetchOnly, Repos: []string{"/g/c"}},
+	}
+	m := &model{groups: groups, groupsMap: buildGroupsMap(groups)}
+	for path, want := range map[string]string{"/g/a": PullRebase, "/g/b": PullRebase, "/g/c": PullFetchOnly, "/g/d": ""} {
+		if got := m.pullStrategy(path); got != want {
+			t.Errorf("pullStrategy(%s) = %q, want %q", path, got, want)
+		}
+	}
+	if m.pullsDirty("/g/d") {
+		t.Error("ungrouped repo pulls dirty without global autostash")
+	}
+	if !m.pullsDirty("/g/b") {
+		t.Error("repo in rebase group does not pull dirty")
+	}
+}
+
+func TestMoveGroupKeepsFavoritesFirst(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	groups := []Group{
+		{Name: "b"},
+		{Name: "Favorites", IsBuiltIn: true, Repos: []string{"/g/x"}},
+		{Name: "a"},
+		{Name: "c"},
+	}
+	m := &model{groups: groups, groupsMap: buildGroupsMap(groups)}
+	if !m.moveGroup("c", -1) {
+		t.Fatal("moveGroup(c, up) did not move")
+	}
+	if m.moveGroup("a", -1) {
+		t.Error("moveGroup moved the first group further up")
+	}
+
+	sorted := append([]Group(nil), m.groups...)
+	sortGroups(sorted)
+	var names []string
+	for _, g := range sorted {
+		names = append(names, g.Name)
+	}
+	if want := []string{"Favorites", "a", "c", "b"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("group order = %v, want %v", names, want)
+	}
+}
+
+func TestMoveReposToGroupMovesAllAndSyncsFavorites(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	groups := []Group{
+		{Name: "Favorites", IsBuiltIn: true, Repos: []string{"/g/a"}},
+		{Name: "work", Repos: []string{"/g/b"}},
+		{Name: "home"},
+	}
+	repos := []Repo{{Name: "a", Path: "/g/a", IsFavorite: true}, {Name: "b", Path: "/g/b"}, {Name: "c", Path: "/g/c"}}
+	m := &model{groups: groups, groupsMap: buildGroupsMap(groups), repos: repos, favorites: map[string]bool{"/g/a": true}}
+
+	m.moveReposToGroup(repos[:2], 2)
+	if got := m.groupsMap["home"].Repos; !reflect.DeepEqual(got, []string{"/g/a", "/g/b"}) {
+		t.Errorf("home repos = %v", got)
+	}
+	if len(m.groupsMap["Favorites"].Repos) != 0 || len(m.groupsMap["work"].Repos) != 0 {
+		t.Errorf("repos left behind: %+v", m.groups)
+	}
+	if m.favorites["/g/a"] || m.repos[0].IsFavorite {
+		t.Error("repo moved out of Favorites is still a favorite")
+	}
+	if m.statusMsg != "Moved 2 repos to home" {
+		t.Errorf("statusMsg = %q", m.statusMsg)
+	}
+}
+
+func TestToggleGroupExpandedPersists(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := &model{expandedGroups: map[string]bool{"home": true}}
+	m.toggleGroupExpanded("work")
+	if got := loadConfig().ExpandedGroups; !reflect.DeepEqual(got, []string{"home", "work"}) {
+		t.Errorf("expanded groups after expanding work = %v", got)
+	}
+	m.toggleGroupExpanded("home")
+	if got := loadConfig().ExpandedGroups; !reflect.DeepEqual(got, []string{"work"}) {
+		t.Errorf("expanded groups after collapsing home = %v", got)
+	}
+}
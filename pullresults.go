@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -94,6 +95,29 @@ func (c *PullResultsCursor) MoveUp() bool {
 	return false
 }
 
+// Index returns the cursor position within the current level
+func (c *PullResultsCursor) Index() int {
+	switch c.Level {
+	case 1:
+		return c.CommitIdx
+	case 2:
+		return c.FileIdx
+	}
+	return c.RepoIdx
+}
+
+// SetIndex moves the cursor within the current level
+func (c *PullResultsCursor) SetIndex(i int) {
+	switch c.Level {
+	case 0:
+		c.RepoIdx = i
+	case 1:
+		c.CommitIdx = i
+	case 2:
+		c.FileIdx = i
+	}
+}
+
 // Reset resets cursor to initial state
 func (c *PullResultsCursor) Reset() {
 	c.Level = 0
@@ -113,6 +137,18 @@ func fetchFilesForCommit(repoPath, commitHash string) ([]FileChange, error) {
 	return parseGitStatOutput(string(output)), nil
 }
 
+// loadCommitFiles fetches a commit's changed files in the background for the
+// pull results file level
+func loadCommitFiles(repoPath, commitHash string) tea.Cmd {
+	return func() tea.Msg {
+		files, err := fetchFilesForCommit(repoPath, commitHash)
+		if err != nil {
+			files = []FileChange{}
+		}
+		return filesLoadedMsg{cacheKey: repoPath + ":" + commitHash, files: files}
+	}
+}
+
 // parseGitStatOutput parses git diff --stat or git show --stat output
 func parseGitStatOutput(output string) []FileChange {
 	var files []FileChange
@@ -156,6 +192,22 @@ func parseGitStatOutput(output string) []FileChange {
 	return files
 }
 
+// pullSkipReason explains why a repo was left out of a pull-behind batch
+func pullSkipReason(repo Repo) string {
+	switch {
+	case repo.Status == StatusUnknown:
+		return "status not loaded"
+	case repo.Status == StatusError:
+		return "status error"
+	case repo.Status == StatusEmpty:
+		return "no commits yet"
+	case !repo.HasUpstream:
+		return "no upstream"
+	default:
+		return "up to date"
+	}
+}
+
 // Styles for pull results
 var (
 	prRepoStyle     = lipgloss.NewStyle().Bold(true)
@@ -166,8 +218,9 @@ var (
 	prDim           = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
 )
 
-// renderPullResultsView renders the entire pull results screen
-func renderPullResultsView(m model) string {
+// pullResultsHeader renders the title and summary above the results tree,
+// up to the blank line before the first row
+func pullResultsHeader(m model) string {
 	title := detailTitleStyle.Render("Pull Results")
 
 	// Calculate summary stats
@@ -184,7 +237,14 @@ func renderPullResultsView(m model) string {
 
 	summary := successStyle.Render(fmt.Sprintf("%d repos updated • %d commits • %d files changed",
 		updatedRepos, totalCommits, totalFiles))
+	if len(m.pullFailed) > 0 {
+		summary += statusErrorStyle.Render(fmt.Sprintf(" • %d failed", len(m.pullFailed)))
+	}
+	return title + "\n\n" + summary + "\n"
+}
 
+// renderPullResultsView renders the entire pull results screen
+func renderPullResultsView(m model) string {
 	// Render tree
 	var content strings.Builder
 	cursor := m.pullResultsCursor
@@ -192,7 +252,7 @@ func renderPullResultsView(m model) string {
 	for i, result := range m.pullResults {
 		// Level 0: Repo line
 		isRepoSelected := cursor.Level == 0 && i == cursor.RepoIdx
-		isRepoExpanded := i == cursor.RepoIdx && cursor.Level >= 1
+		isRepoExpanded := (i == cursor.RepoIdx && cursor.Level >= 1) || m.pullResultsExpandAll
 
 		repoLine := renderRepoLine(result, isRepoSelected, isRepoExpanded)
 		content.WriteString(repoLine + "\n")
@@ -200,16 +260,17 @@ func renderPullResultsView(m model) string {
 		// Level 1: Commits (only if this repo is expanded)
 		if isRepoExpanded {
 			for j, commit := range result.Commits {
-				isCommitSelected := cursor.Level == 1 && j == cursor.CommitIdx
-				isCommitExpanded := j == cursor.CommitIdx && cursor.Level == 2
+				inCursorRepo := i == cursor.RepoIdx
+				isCommitSelected := inCursorRepo && cursor.Level == 1 && j == cursor.CommitIdx
+				isCommitExpanded := inCursorRepo && j == cursor.CommitIdx && cursor.Level == 2
 
-				commitLine := renderCommitLine(commit, isCommitSelected, isCommitExpanded)
+				commitLine := renderCommitLine(commit, isCommitSelected, isCommitExpanded, m.width)
 				content.WriteString(commitLine + "\n")
 
 				// Level 2: Files (only if this commit is expanded)
 				if isCommitExpanded {
 					cacheKey := result.RepoPath + ":" + commit.Hash
-					files := m.filesCache[cacheKey]
+					files, loaded := m.filesCache[cacheKey]
 
 					// Calculate max path width for alignment
 					maxPathWidth := 20
@@ -228,8 +289,10 @@ func renderPullResultsView(m model) string {
 						content.WriteString(fileLine + "\n")
 					}
 
-					if len(files) == 0 {
+					if !loaded {
 						content.WriteString("          " + prDim.Render("(loading files...)") + "\n")
+					} else if len(files) == 0 {
+						content.WriteString("          " + prDim.Render("(no file changes)") + "\n")
 					}
 				}
 			}
@@ -237,12 +300,72 @@ func renderPullResultsView(m model) string {
 	}
 
 	if len(m.pullResults) == 0 {
-		content.WriteString(prDim.Render("  No pull results to show"))
+		content.WriteString(prDim.Render("  No pull results to show") + "\n")
 	}
 
-	help := helpStyle.Render("↑/↓: navigate • →/enter: expand • ←: collapse • esc: back")
+	// Failed repos (not navigable, 'r' retries them all)
+	if len(m.pullFailed) > 0 {
+		content.WriteString("\n" + statusErrorStyle.Render(fmt.Sprintf("Failed (%d)", len(m.pullFailed))) + "\n")
+		for _, f := range m.pullFailed {
+			content.WriteString("  " + statusErrorStyle.Render("✗ "+f.RepoName+": "+f.Err) + "\n")
+		}
+	}
+
+	// Skipped repos (not navigable, just listed with reasons)
+	if len(m.pullSkipped) > 0 {
+		content.WriteString("\n" + statusDirtyStyle.Render(fmt.Sprintf("Skipped (%d)", len(m.pullSkipped))) + "\n")
+		for _, s := range m.pullSkipped {
+			content.WriteString("  " + prDim.Render("− "+s.RepoName+": "+s.Reason) + "\n")
+		}
+	}
 
-	return title + "\n\n" + summary + "\n\n" + content.String() + "\n" + help
+	keys := "↑/↓: navigate • →/enter: expand • ←: collapse • e/c: expand/collapse all • esc: back"
+	if len(m.retryablePulls()) > 0 {
+		keys = "r: retry failed • " + keys
+	}
+	help := helpStyle.Render(keys)
+
+	return pullResultsHeader(m) + "\n" + content.String() + "\n" + help
+}
+
+// pullResultsRowAt returns the cursor position of the tree row at the given
+// line, following the same expansion rules as renderPullResultsView.
+// Status lines like "(loading files...)" are not selectable.
+func pullResultsRowAt(m model, row int) (PullResultsCursor, bool) {
+	if row < 0 {
+		return PullResultsCursor{}, false
+	}
+	cursor := m.pullResultsCursor
+	line := 0
+	for i, result := range m.pullResults {
+		if line == row {
+			return PullResultsCursor{Level: 0, RepoIdx: i}, true
+		}
+		line++
+		if !((i == cursor.RepoIdx && cursor.Level >= 1) || m.pullResultsExpandAll) {
+			continue
+		}
+		for j, commit := range result.Commits {
+			if line == row {
+				return PullResultsCursor{Level: 1, RepoIdx: i, CommitIdx: j}, true
+			}
+			line++
+			if !(i == cursor.RepoIdx && j == cursor.CommitIdx && cursor.Level == 2) {
+				continue
+			}
+			files, loaded := m.filesCache[result.RepoPath+":"+commit.Hash]
+			for k := range files {
+				if line == row {
+					return PullResultsCursor{Level: 2, RepoIdx: i, CommitIdx: j, FileIdx: k}, true
+				}
+				line++
+			}
+			if !loaded || len(files) == 0 {
+				line++
+			}
+		}
+	}
+	return PullResultsCursor{}, false
 }
 
 // renderRepoLine renders a single repo line
@@ -263,9 +386,15 @@ func renderRepoLine(result PullResultInfo, isSelected, isExpanded bool) string {
 	}
 
 	info := fmt.Sprintf(" (%d commits, %d files)", len(result.Commits), result.FilesChanged)
+	if result.Elapsed > 0 {
+		info = fmt.Sprintf(" (%d commits, %d files, %s)", len(result.Commits), result.FilesChanged, formatElapsed(result.Elapsed))
+	}
 	if !result.Updated {
 		info = " (up to date)"
 	}
+	if result.Hook != "" {
+		info += " · hook: " + truncateRunes(result.Hook, 50)
+	}
 
 	line := fmt.Sprintf("%s %s %s%s", expandIcon, statusIcon, result.RepoName, info)
 
@@ -278,8 +407,9 @@ func renderRepoLine(result PullResultInfo, isSelected, isExpanded bool) string {
 	return prefix + prDim.Render(line)
 }
 
-// renderCommitLine renders a single commit line
-func renderCommitLine(commit CommitInfo, isSelected, isExpanded bool) string {
+// renderCommitLine renders a single commit line, followed by the dimmed author
+// and relative time when the terminal width allows
+func renderCommitLine(commit CommitInfo, isSelected, isExpanded bool, width int) string {
 	prefix := "      "
 	if isSelected {
 		prefix = "    > "
@@ -290,18 +420,43 @@ func renderCommitLine(commit CommitInfo, isSelected, isExpanded bool) string {
 		expandIcon = "▼"
 	}
 
-	hash := prCommitHash.Render(commit.Hash)
+	if width <= 0 {
+		width = 80
+	}
+	// Room for the message after the prefix, icon and hash
+	available := width - len(prefix) - 2 - len(commit.Hash) - 1
+
+	// Drop the author, then the time, before squeezing the message too far
+	var meta []string
+	if commit.Author != "" {
+		meta = append(meta, commit.Author)
+	}
+	if commit.Time != "" {
+		meta = append(meta, commit.Time)
+	}
+	metaText := ""
+	for len(meta) > 0 {
+		metaText = " · " + strings.Join(meta, " · ")
+		if available-len([]rune(metaText)) >= 20 {
+			break
+		}
+		meta = meta[1:]
+		metaText = ""
+	}
+
 	message := commit.Message
-	if len(message) > 50 {
-		message = message[:47] + "..."
+	maxMessage := min(50, available-len([]rune(metaText)))
+	if len([]rune(message)) > maxMessage {
+		message = truncateRunes(message, max(maxMessage-3, 1))
 	}
 
+	hash := prCommitHash.Render(commit.Hash)
 	line := fmt.Sprintf("%s %s %s", expandIcon, hash, message)
 
 	if isSelected {
-		return prefix + prSelected.Render(line)
+		return prefix + prSelected.Render(line) + prDim.Render(metaText)
 	}
-	return prefix + line
+	return prefix + line + prDim.Render(metaText)
 }
 
 // renderFileLine renders a single file change line with aligned columns
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderPullResultsListsFailures(t *testing.T) {
+	m := model{pullFailed: []FailedPull{{RepoPath: "/g/api", RepoName: "api", Err: "fatal: Not possible to fast-forward, aborting."}}}
+	out := renderPullResultsView(m)
+	for _, want := range []string{"Failed (1)", "api: fatal: Not possible to fast-forward", "r: retry failed", "1 failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("pull results view missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderPullResultsDoesNotRetryConflicts(t *testing.T) {
+	m := model{pullFailed: []FailedPull{{RepoPath: "/g/api", RepoName: "api", Err: "pulled, but reapplying the autostash conflicted", Conflicted: true}}}
+	out := renderPullResultsView(m)
+	if !strings.Contains(out, "api: pulled, but reapplying the autostash conflicted") {
+		t.Errorf("pull results view missing the conflict:\n%s", out)
+	}
+	if strings.Contains(out, "r: retry failed") {
+		t.Errorf("pull results view offers to retry a conflicted pull:\n%s", out)
+	}
+}
@@ -1,6 +1,10 @@
 package main
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 var (
 	titleStyle        = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
@@ -12,6 +16,74 @@ var (
 	helpStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
 	successStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
 	pullResultStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+	stashStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("141"))
+	recentStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	staleStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	selectedStyle     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
 	detailTitleStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).Padding(0, 1)
 	detailBorderStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("62")).Padding(1, 2)
 )
+
+// parseThemeColor turns a theme value into a color. "light,dark" gives an
+// adaptive color for light and dark terminals; anything else (a 256-color
+// number or "#rrggbb") is used as-is.
+func parseThemeColor(value string) (lipgloss.TerminalColor, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, false
+	}
+	if light, dark, ok := strings.Cut(value, ","); ok {
+		light, dark = strings.TrimSpace(light), strings.TrimSpace(dark)
+		if light == "" || dark == "" {
+			return nil, false
+		}
+		return lipgloss.AdaptiveColor{Light: light, Dark: dark}, true
+	}
+	return lipgloss.Color(value), true
+}
+
+// applyTheme overrides the default style colors with those set in the
+// config's theme. Unknown names and empty values are ignored.
+func applyTheme(theme map[string]string) {
+	for name, value := range theme {
+		color, ok := parseThemeColor(value)
+		if !ok {
+			continue
+		}
+		switch name {
+		case "title":
+			titleStyle = titleStyle.Foreground(color)
+			detailTitleStyle = detailTitleStyle.Foreground(color)
+		case "selected":
+			selectedStyle = selectedStyle.Foreground(color)
+			prSelected = prSelected.Foreground(color)
+		case "clean":
+			statusCleanStyle = statusCleanStyle.Foreground(color)
+			successStyle = successStyle.Foreground(color)
+			prAdditions = prAdditions.Foreground(color)
+		case "dirty":
+			statusDirtyStyle = statusDirtyStyle.Foreground(color)
+		case "error":
+			statusErrorStyle = statusErrorStyle.Foreground(color)
+			prDeletions = prDeletions.Foreground(color)
+		case "hash":
+			prCommitHash = prCommitHash.Foreground(color)
+		case "favorite":
+			favoriteStyle = favoriteStyle.Foreground(color)
+		case "branch":
+			branchStyle = branchStyle.Foreground(color)
+			pullResultStyle = pullResultStyle.Foreground(color)
+		case "help":
+			helpStyle = helpStyle.Foreground(color)
+			prDim = prDim.Foreground(color)
+		case "stash":
+			stashStyle = stashStyle.Foreground(color)
+		case "recent":
+			recentStyle = recentStyle.Foreground(color)
+		case "stale":
+			staleStyle = staleStyle.Foreground(color)
+		case "border":
+			detailBorderStyle = detailBorderStyle.BorderForeground(color)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestParseThemeColorPlain(t *testing.T) {
+	color, ok := parseThemeColor("244")
+	if !ok {
+		t.Fatal("expected color to parse")
+	}
+	if color != lipgloss.Color("244") {
+		t.Errorf("expected Color(244), got %#v", color)
+	}
+}
+
+func TestParseThemeColorAdaptive(t *testing.T) {
+	color, ok := parseThemeColor("236, 250")
+	if !ok {
+		t.Fatal("expected adaptive color to parse")
+	}
+	want := lipgloss.AdaptiveColor{Light: "236", Dark: "250"}
+	if color != want {
+		t.Errorf("expected %#v, got %#v", want, color)
+	}
+}
+
+func TestParseThemeColorInvalid(t *testing.T) {
+	for _, value := range []string{"", "  ", "236,", ",250"} {
+		if _, ok := parseThemeColor(value); ok {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
@@ -1,6 +1,13 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
 
 // GitStatus represents the status of a git repository
 type GitStatus int
@@ -11,18 +18,36 @@ const (
 	StatusCleanBehind // clean locally but behind remote
 	StatusDirty
 	StatusError
+	StatusConflict // unmerged paths from a failed merge, rebase or pull
+	StatusEmpty    // no commits yet (fresh `git init` or empty clone)
 )
 
 // Repo represents a git repository
 type Repo struct {
-	Path        string
-	Name        string
-	Branch      string
-	Status      GitStatus
-	StatusText  string
-	IsFavorite  bool
-	PullResult  string
-	BehindCount int
+	Path           string
+	Name           string
+	Branch         string
+	Status         GitStatus
+	StatusText     string
+	IsFavorite     bool
+	PullResult     string
+	BehindCount    int
+	StashCount     int
+	HasUpstream    bool
+	UpstreamAge    string    // age of newest upstream commit, e.g. "2 hours ago"
+	LastCommitTime int64     // unix timestamp of HEAD commit, 0 if unknown
+	LastCommit     string    // relative age of HEAD commit, e.g. "3 days ago"
+	LastSubject    string    // subject line of HEAD commit
+	Operation      string    // "merge" or "rebase" stopped half-way, "" if none
+	Detached       bool      // HEAD is not on a branch
+	LastPulled     time.Time // last successful pull through guppi, zero if never
+	RemoteURL      string    // origin URL read from .git/config during the scan
+	StatusErr      string    // git's error output when Status is StatusError
+	LatestTag      string    // nearest tag reachable from HEAD, "" if none
+	TagDistance    int       // commits HEAD is past LatestTag, 0 when on the tag
+	ChangedCount   int       // files with local changes, 0 when clean
+	Nested         bool      // listed under an expanded group on the homepage
+	filterText     string    // what '/' matches, built from the search fields
 }
 
 func (r Repo) Title() string {
@@ -37,39 +62,71 @@ func (r Repo) Title() string {
 	return star + r.Name + branch
 }
 
-func (r Repo) Description() string {
-	var status string
-	switch r.Status {
-	case StatusClean:
-		status = statusCleanStyle.Render("✓ clean")
-	case StatusCleanBehind:
-		status = statusDirtyStyle.Render(fmt.Sprintf("↓ %d behind", r.BehindCount))
-	case StatusDirty:
-		if r.BehindCount > 0 {
-			status = statusDirtyStyle.Render(fmt.Sprintf("● %s | ↓ %d behind", r.StatusText, r.BehindCount))
-		} else {
-			status = statusDirtyStyle.Render("● " + r.StatusText)
-		}
-	case StatusError:
-		status = statusErrorStyle.Render("✗ " + r.StatusText)
-	default:
-		status = "..."
+// hasLocalChanges reports whether the working tree is dirty, including conflicts
+func (r Repo) hasLocalChanges() bool {
+	return r.Status == StatusDirty || r.Status == StatusConflict
+}
+
+// tagLabel describes HEAD relative to the nearest tag, e.g. "v1.2.0" when on
+// it or "v1.2.0+3" when three commits past it
+func (r Repo) tagLabel() string {
+	if r.LatestTag == "" || r.TagDistance == 0 {
+		return r.LatestTag
 	}
+	return fmt.Sprintf("%s+%d", r.LatestTag, r.TagDistance)
+}
 
-	if r.PullResult != "" {
-		status += " | " + pullResultStyle.Render(r.PullResult)
+// upstreamAgeSuffix describes how fresh the newest incoming commit is
+func (r Repo) upstreamAgeSuffix() string {
+	if r.UpstreamAge == "" {
+		return ""
 	}
+	return " (newest " + r.UpstreamAge + ")"
+}
+
+// defaultSearchFields are the repo fields '/' matches when searchFields isn't set
+var defaultSearchFields = []string{"name", "path", "remote"}
 
-	return status
+// FilterValue is the text '/' matches, see repoSearchText
+func (r Repo) FilterValue() string {
+	if r.filterText == "" {
+		return r.Name
+	}
+	return r.filterText
 }
 
-func (r Repo) FilterValue() string { return r.Name }
+// repoSearchText joins the given search fields so '/' can match an org in
+// the remote URL or a path segment as well as the name. Paths are matched
+// relative to the git directory so its own path doesn't match everything.
+func repoSearchText(r Repo, fields []string) string {
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		switch field {
+		case "name":
+			parts = append(parts, r.Name)
+		case "path":
+			parts = append(parts, relRepoPath(r.Path))
+		case "remote":
+			if r.RemoteURL != "" {
+				parts = append(parts, r.RemoteURL)
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
 
 // Group represents a collection of repos
 type Group struct {
-	Name      string   `json:"name"`
-	Repos     []string `json:"repos"` // repo paths
-	IsBuiltIn bool     `json:"-"`     // runtime flag for Favorites
+	Name         string     `json:"name"`
+	Repos        []string   `json:"repos"`                  // repo paths
+	PostPullHook string     `json:"postPullHook,omitempty"` // command run after a pull updates a repo in this group
+	Parent       string     `json:"parent,omitempty"`       // name of the parent group, "" for top-level
+	Auto         bool       `json:"auto,omitempty"`         // created by auto-grouping by remote host
+	AutoRepos    []string   `json:"autoRepos,omitempty"`    // repos assigned by auto-grouping (removed on undo)
+	PullStrategy string     `json:"pullStrategy,omitempty"` // "" = global setting, else PullFFOnly, PullRebase or PullFetchOnly
+	FetchMode    *FetchMode `json:"fetchMode,omitempty"`    // nil = global fetch mode, used for refreshes inside the group
+	Order        int        `json:"order,omitempty"`        // manual position among sibling groups, 0 = unordered (after ordered groups, alphabetically)
+	IsBuiltIn    bool       `json:"-"`                      // runtime flag for Favorites
 }
 
 // GroupItem is used for list display
@@ -87,10 +144,37 @@ func (g GroupItem) FilterValue() string { return g.Name }
 // BranchInfo contains information about a git branch
 type BranchInfo struct {
 	Name       string
-	IsLocal    bool   // exists locally
-	IsRemote   bool   // exists on remote
+	IsLocal    bool // exists locally
+	IsRemote   bool // exists on remote
 	IsCurrent  bool
 	RemoteName string // e.g., "origin/main" if tracking
+	Ahead      int    // commits not yet on upstream (tracking branches only)
+	Behind     int    // upstream commits not yet local (tracking branches only)
+	IsDefault  bool   // the repo's default branch (origin/HEAD, or a common name)
+	NoUpstream bool   // local branch with no tracking branch set, so Ahead/Behind stay 0
+}
+
+// ChangedFile is a file from `git status --porcelain` with its XY status code
+type ChangedFile struct {
+	Path     string
+	Index    byte // staged status (X), ' ' if unchanged
+	Worktree byte // unstaged status (Y), ' ' if unchanged
+}
+
+// Untracked reports whether git doesn't track the file yet
+func (f ChangedFile) Untracked() bool { return f.Index == '?' }
+
+// Staged reports whether the file has staged changes
+func (f ChangedFile) Staged() bool { return f.Index != ' ' && f.Index != '?' }
+
+// Unstaged reports whether the file has changes not yet staged
+func (f ChangedFile) Unstaged() bool { return f.Worktree != ' ' }
+
+// StashInfo contains information about a stash entry
+type StashInfo struct {
+	Ref     string // e.g., "stash@{0}"
+	Message string
+	Time    string // relative, e.g. "2 hours ago"
 }
 
 // viewMode represents the current view state
@@ -103,11 +187,22 @@ const (
 	actionSelectView
 	errorView
 	settingsView
-	groupInputView     // text input for group name (new/rename)
-	groupDeleteView    // confirm group deletion
-	groupSelectView    // select group to move repo to
-	groupAddReposView  // select repos to add to group
-	pullResultsView    // show results after pull operations
+	groupInputView    // text input for group name (new/rename)
+	groupDeleteView   // confirm group deletion
+	groupSelectView   // select group to move repo to
+	groupAddReposView // select repos to add to group
+	pullResultsView   // show results after pull operations
+	reflogView        // show reflog for current branch
+	stashView         // list and manage stashes
+	branchInputView   // text input for branch name (new/rename)
+	cloneInputView    // text input for a repo URL to clone
+	helpView          // scrollable key binding overlay
+	commitInputView   // text input for a commit message
+	diffView          // scrollable diff of a changed file
+	pullPreviewView   // dry-run summary of what a batch pull would do
+	groupSettingsView // per-group pull strategy and fetch mode overrides
+	commitLogView     // paginated, searchable commit history
+	commitShowView    // full message and diffstat of one commit
 )
 
 // switchAction represents actions for handling uncommitted changes
@@ -132,15 +227,35 @@ const (
 // Message types for async operations
 
 type repoFoundMsg struct {
-	repos []Repo
+	repos         []Repo
+	invalidPinned []string // pinned paths that aren't git repos
+	unreadable    int      // subdirectories skipped because they couldn't be read
+	err           error    // the git directory itself couldn't be scanned
 }
 
 type statusUpdatedMsg struct {
-	path        string
-	branch      string
-	status      GitStatus
-	text        string
-	behindCount int
+	path           string
+	branch         string
+	status         GitStatus
+	text           string
+	changed        int // files with local changes
+	behindCount    int
+	stashCount     int
+	hasUpstream    bool
+	upstreamAge    string
+	lastCommitTime int64
+	lastCommit     string
+	lastSubject    string
+	operation      string // "merge" or "rebase" stopped half-way, "" if none
+	detached       bool
+	errDetail      string // git's error output when status is StatusError
+	latestTag      string // nearest tag reachable from HEAD, "" if none
+	tagDistance    int    // commits HEAD is past latestTag
+}
+
+type fetchCompleteMsg struct {
+	path   string
+	failed bool
 }
 
 type pullCompleteMsg struct {
@@ -148,17 +263,85 @@ type pullCompleteMsg struct {
 	result      string // full output for error display
 	shortResult string // shortened for list display
 	err         error
+	elapsed     time.Duration
+	conflicted  bool // pulled, but reapplying autostashed changes conflicted
+	fetchOnly   bool // the repo's group pulls fetch-only, so nothing was merged
 }
 
 type detailLoadedMsg struct {
 	path    string
+	status  string        // branch line from git status, e.g. "## main...origin/main"
+	content string        // remaining detail sections (diff stats, commits, remotes)
+	files   []ChangedFile // changed files for the status pane
+}
+
+type commitLogLoadedMsg struct {
+	path    string
+	query   string // message search the page was loaded for
+	skip    int    // commits skipped, i.e. where this page starts
+	commits []CommitInfo
+	err     string
+}
+
+type commitShowLoadedMsg struct {
+	path    string
+	hash    string
 	content string
 }
 
-type branchesLoadedMsg struct {
+type opAbortedMsg struct {
+	path string
+	op   string // "merge" or "rebase"
+	err  string // git's output when the abort failed
+}
+
+type rebaseResultMsg struct {
+	path      string
+	onto      string
+	output    string // git's combined output
+	err       error
+	conflicts int // unmerged paths left behind by a failed rebase
+}
+
+type diskUsageMsg struct {
 	path     string
-	branches []BranchInfo
-	current  string
+	worktree int64 // bytes in the working tree, .git excluded
+	objects  int64 // bytes in the object store (loose objects and packs)
+	err      error
+}
+
+type remoteHostsLoadedMsg struct {
+	hosts map[string]string // repo path -> origin host
+}
+
+type safeDirectoryAddedMsg struct {
+	path string
+	err  error
+}
+
+type fileDiffLoadedMsg struct {
+	path    string
+	file    string
+	content string
+}
+
+type stageToggledMsg struct {
+	path   string
+	file   string
+	staged bool // true if the file was staged, false if unstaged
+	err    string
+}
+
+type reflogLoadedMsg struct {
+	path    string
+	content string
+}
+
+type branchesLoadedMsg struct {
+	path          string
+	branches      []BranchInfo
+	current       string
+	defaultBranch string
 }
 
 type branchDeleteMsg struct {
@@ -166,15 +349,64 @@ type branchDeleteMsg struct {
 	branch  string
 	success bool
 	err     string
+	tip     string // abbreviated commit the branch pointed at, for recovery
 }
 
-type branchCreateMsg struct {
+type unmergedCheckedMsg struct {
+	path     string
+	branch   string
+	unmerged int // commits deleting the branch would lose
+	err      error
+}
+
+type remoteBranchDeleteMsg struct {
 	path    string
+	remote  string
 	branch  string
 	success bool
 	err     string
 }
 
+type branchCreateMsg struct {
+	path     string
+	branch   string
+	checkout bool // new branch was also checked out
+	success  bool
+	err      string
+}
+
+type branchRenameMsg struct {
+	path    string
+	oldName string
+	newName string
+	success bool
+	err     string
+}
+
+type upstreamSetMsg struct {
+	path     string
+	branch   string
+	upstream string
+	success  bool
+	err      string
+}
+
+type commitMsg struct {
+	path    string
+	output  string
+	success bool
+	err     string
+}
+
+type switchCheckedMsg struct {
+	path      string
+	branch    string   // branch as listed
+	ref       string   // what to check out: the branch or its remote
+	tracked   int      // files with tracked changes
+	untracked []string // untracked files git would carry across
+	clobbered []string // untracked files the checkout would overwrite
+}
+
 type branchSwitchMsg struct {
 	path    string
 	branch  string
@@ -188,11 +420,92 @@ type stashResultMsg struct {
 	err     string
 }
 
-type lazygitExitMsg struct {
+type stashesLoadedMsg struct {
+	path    string
+	stashes []StashInfo
+}
+
+// filesLoadedMsg carries a commit's changed files for the pull results tree
+type filesLoadedMsg struct {
+	cacheKey string // "repoPath:commitHash"
+	files    []FileChange
+}
+
+// upstreamCheckedMsg carries the upstream a reset would move to
+type upstreamCheckedMsg struct {
+	path     string
+	upstream string
+	ahead    int // local commits the reset would drop
+	err      error
+}
+
+// resetHardMsg reports a reset --hard to the upstream
+type resetHardMsg struct {
+	path    string
+	success bool
+	err     string
+}
+
+// autoStashPopMsg reports restoring the latest guppi auto-stash
+type autoStashPopMsg struct {
+	path     string
+	ref      string
+	success  bool
+	notFound bool
+	err      string
+}
+
+type stashActionMsg struct {
+	path    string
+	action  string // "apply", "pop" or "drop"
+	ref     string
+	success bool
+	err     string
+}
+
+type gitUIExitMsg struct {
+	path string
+	err  error
+}
+
+type hookResultMsg struct {
+	path    string
+	command string
+	output  string
+	err     error
+}
+
+type editorExitMsg struct {
 	path string
 	err  error
 }
 
+type cmdStartedMsg struct {
+	proc   *exec.Cmd
+	stream chan tea.Msg
+}
+
+type cmdOutputLineMsg struct {
+	line   string
+	stream chan tea.Msg // to keep reading the running command's output
+}
+
+type autoRefreshMsg struct {
+	gen int // matches model.autoRefreshGen unless the interval changed since scheduling
+}
+
+type cloneProgressMsg struct {
+	line   string
+	stream chan tea.Msg // to keep reading the clone's progress
+}
+
+type cloneCompleteMsg struct {
+	path   string
+	name   string
+	output string // full git output for error display
+	err    error
+}
+
 type cmdResultMsg struct {
 	output string
 	err    error
@@ -212,7 +525,54 @@ type PullResultInfo struct {
 	RepoName     string
 	Commits      []CommitInfo
 	FilesChanged int
-	Updated      bool // true if actually pulled new commits
+	Updated      bool          // true if actually pulled new commits
+	Elapsed      time.Duration // how long the pull took
+	Hook         string        // post-pull hook's last output line, once it ran
+}
+
+// SkippedRepo records a repo left out of a batch pull and why
+type SkippedRepo struct {
+	RepoName string
+	Reason   string
+}
+
+// FailedPull records a repo whose pull failed in a batch, so it can be retried
+type FailedPull struct {
+	RepoPath   string
+	RepoName   string
+	Err        string // first line of git's output
+	Conflicted bool   // pulled, but the autostash conflicted; not retried
+}
+
+// PullPreviewEntry is what a pull would do to one repo, measured after a fetch
+type PullPreviewEntry struct {
+	RepoPath    string
+	RepoName    string
+	Behind      int
+	Ahead       int
+	HasUpstream bool
+	Dirty       bool
+	Strategy    string // how the repo is pulled: PullFFOnly, PullRebase or PullFetchOnly
+}
+
+// Reason describes why the entry would not be pulled, or "" if pulling
+// would fast-forward or rebase it
+func (e PullPreviewEntry) Reason() string {
+	switch {
+	case !e.HasUpstream:
+		return "no upstream"
+	case e.Behind == 0:
+		return "up to date"
+	case e.Strategy == PullFetchOnly:
+		return "group pulls fetch-only"
+	case e.Ahead > 0 && e.Strategy != PullRebase:
+		return "diverged from upstream"
+	}
+	return ""
+}
+
+type pullPreviewMsg struct {
+	entries []PullPreviewEntry
 }
 
 type pullResultsReadyMsg struct {
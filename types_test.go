@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestPullPreviewEntryReason(t *testing.T) {
+	cases := []struct {
+		entry PullPreviewEntry
+		want  string
+	}{
+		{PullPreviewEntry{HasUpstream: true, Behind: 3}, ""},
+		{PullPreviewEntry{HasUpstream: true}, "up to date"},
+		{PullPreviewEntry{HasUpstream: true, Behind: 2, Ahead: 1}, "diverged from upstream"},
+		{PullPreviewEntry{Behind: 2}, "no upstream"},
+		{PullPreviewEntry{HasUpstream: true, Behind: 2, Ahead: 1, Strategy: PullRebase}, ""},
+		{PullPreviewEntry{HasUpstream: true, Behind: 2, Strategy: PullFetchOnly}, "group pulls fetch-only"},
+	}
+	for _, c := range cases {
+		if got := c.entry.Reason(); got != c.want {
+			t.Errorf("%+v: Reason() = %q, want %q", c.entry, got, c.want)
+		}
+	}
+}
+
+func TestRepoSearchTextUsesSearchFields(t *testing.T) {
+	setRepoBaseDir("/home/me/git")
+	t.Cleanup(func() { repoBaseDir = "" })
+	repo := Repo{Name: "app", Path: "/home/me/git/work/app", RemoteURL: "git@github.com:acme/app.git"}
+
+	if got := repoSearchText(repo, defaultSearchFields); got != "app work/app git@github.com:acme/app.git" {
+		t.Errorf("default search text = %q", got)
+	}
+	if got := repoSearchText(repo, []string{"name"}); got != "app" {
+		t.Errorf("name-only search text = %q", got)
+	}
+	if got := repo.FilterValue(); got != "app" {
+		t.Errorf("FilterValue() without search text = %q, want the name", got)
+	}
+}
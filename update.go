@@ -6,12 +6,14 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -21,20 +23,52 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.list.SetSize(msg.Width, msg.Height-5)
+		listHeight := msg.Height - 5
+		if msg.Height < 20 {
+			listHeight = msg.Height - 4 // second help line is hidden
+		}
+		if listHeight < 1 {
+			listHeight = 1
+		}
+		m.list.SetSize(msg.Width, listHeight)
 		m.viewport.Width = msg.Width - 4
 		m.viewport.Height = msg.Height - 8
+		if m.mode == errorView {
+			m.viewport.SetContent(wrapText(m.errorMsg, m.viewport.Width))
+		}
+
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
 
 	case tea.KeyMsg:
+		// A quit request only stays armed until the next key
+		quitArmed := m.quitKey != "" && m.quitKey == msg.String()
+		m.quitKey = ""
+
 		// Handle pull results view keys
 		if m.mode == pullResultsView {
 			switch msg.String() {
 			case "q", "esc":
 				m.mode = listView
 				m.pullResults = nil
+				m.pullSkipped = nil
+				m.pullFailed = nil
 				m.pullResultsCursor.Reset()
+				m.pullResultsExpandAll = false
 				m.filesCache = make(map[string][]FileChange)
+				m.filesLoading = make(map[string]bool)
 				return m, nil
+			case "r":
+				paths := m.retryablePulls()
+				if len(paths) == 0 {
+					return m, nil
+				}
+				m.mode = listView
+				m.pullResultsCursor.Reset()
+				m.pullResultsExpandAll = false
+				m.filesCache = make(map[string][]FileChange)
+				m.filesLoading = make(map[string]bool)
+				return m, tea.Batch(m.retryPulls(paths)...)
 			case "up", "k":
 				// Move up within level, or go up a level if at top
 				maxItems := m.getPullResultsMaxItems()
@@ -48,6 +82,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				maxItems := m.getPullResultsMaxItems()
 				m.pullResultsCursor.MoveDown(maxItems)
 				return m, nil
+			case "pgup", "pgdown", "home", "end", "g", "G":
+				index := jumpIndex(msg.String(), m.pullResultsCursor.Index(), m.getPullResultsMaxItems(), m.viewport.Height)
+				m.pullResultsCursor.SetIndex(index)
+				return m, nil
 			case "right", "enter", "l":
 				// Go deeper - fetch files if entering file level
 				if m.pullResultsCursor.Level == 1 {
@@ -57,14 +95,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						if m.pullResultsCursor.CommitIdx < len(result.Commits) {
 							commit := result.Commits[m.pullResultsCursor.CommitIdx]
 							cacheKey := result.RepoPath + ":" + commit.Hash
-							if _, ok := m.filesCache[cacheKey]; !ok {
-								// Fetch files synchronously (fast enough for single commit)
-								files, err := fetchFilesForCommit(result.RepoPath, commit.Hash)
-								if err == nil {
-									m.filesCache[cacheKey] = files
-								} else {
-									m.filesCache[cacheKey] = []FileChange{}
-								}
+							_, cached := m.filesCache[cacheKey]
+							if !cached && !m.filesLoading[cacheKey] {
+								m.filesLoading[cacheKey] = true
+								m.pullResultsCursor.GoDeeper()
+								return m, loadCommitFiles(result.RepoPath, commit.Hash)
 							}
 						}
 					}
@@ -74,6 +109,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "left", "h":
 				m.pullResultsCursor.GoUp()
 				return m, nil
+			case "e":
+				// Expand every repo to its commits; files still load when a
+				// commit is drilled into
+				m.pullResultsExpandAll = true
+				return m, nil
+			case "c":
+				m.pullResultsExpandAll = false
+				m.pullResultsCursor.Level = 0
+				return m, nil
 			}
 			return m, nil
 		}
@@ -86,9 +130,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.dirInput.SetValue(m.gitDir)
 				return m, nil
 			case "enter":
-				newDir := m.dirInput.Value()
+				newDir := expandHome(strings.TrimSpace(m.dirInput.Value()))
 				if info, err := os.Stat(newDir); err == nil && info.IsDir() {
-					m.gitDir = newDir
+					m.changeGitDir(newDir)
 					m.mode = listView
 					m.scanning = true
 					m.repos = []Repo{}
@@ -107,17 +151,157 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Handle detail view keys
 		if m.mode == detailView {
+			// Confirm remote branch deletion
+			if m.remoteDelete != "" {
+				remoteName := m.remoteDelete
+				m.remoteDelete = ""
+				switch msg.String() {
+				case "y", "Y":
+					remote, branch, ok := strings.Cut(remoteName, "/")
+					if !ok || m.detailRepo == nil {
+						m.statusMsg = "Cannot parse remote branch: " + remoteName
+						return m, nil
+					}
+					m.statusMsg = "Deleting " + remoteName + " on remote..."
+					return m, deleteRemoteBranch(m.detailRepo.Path, remote, branch)
+				}
+				m.statusMsg = "Remote delete cancelled"
+				return m, nil
+			}
+
+			// Confirm force-deleting a branch with unmerged commits
+			if m.forceDelete != "" {
+				branch := m.forceDelete
+				m.forceDelete = ""
+				if (msg.String() == "y" || msg.String() == "Y") && m.detailRepo != nil {
+					m.statusMsg = "Force deleting " + branch + "..."
+					return m, deleteBranch(m.detailRepo.Path, branch, true)
+				}
+				m.statusMsg = "Force delete cancelled"
+				return m, nil
+			}
+
+			// Confirm hard reset to upstream, twice since it discards work
+			if m.resetConfirm > 0 {
+				if (msg.String() == "y" || msg.String() == "Y") && m.detailRepo != nil {
+					if m.resetConfirm == 1 {
+						m.resetConfirm = 2
+						return m, nil
+					}
+					m.resetConfirm = 0
+					m.statusMsg = "Resetting to " + m.resetUpstream + "..."
+					return m, resetToUpstream(m.detailRepo.Path)
+				}
+				m.resetConfirm = 0
+				m.statusMsg = "Reset cancelled"
+				return m, nil
+			}
+
+			// Confirm aborting a merge or rebase
+			if m.abortOp != "" {
+				op := m.abortOp
+				m.abortOp = ""
+				if (msg.String() == "y" || msg.String() == "Y") && m.detailRepo != nil {
+					m.statusMsg = "Aborting " + op + "..."
+					return m, abortOperation(m.detailRepo.Path, op)
+				}
+				m.statusMsg = "Abort cancelled"
+				return m, nil
+			}
+
+			// Confirm rebasing the current branch
+			if m.rebaseOnto != "" {
+				onto := m.rebaseOnto
+				m.rebaseOnto = ""
+				if (msg.String() == "y" || msg.String() == "Y") && m.detailRepo != nil {
+					m.statusMsg = "Rebasing onto " + onto + "..."
+					return m, rebaseBranch(m.detailRepo.Path, onto)
+				}
+				m.statusMsg = "Rebase cancelled"
+				return m, nil
+			}
+
+			// Confirm switching branches with untracked files in the tree
+			if m.pendingSwitch != "" {
+				target := m.pendingSwitch
+				m.pendingSwitch = ""
+				if (msg.String() == "y" || msg.String() == "Y") && m.detailRepo != nil {
+					m.statusMsg = "Switching to " + m.targetBranch + "..."
+					return m, switchBranch(m.detailRepo.Path, target)
+				}
+				m.statusMsg = "Switch cancelled"
+				return m, nil
+			}
+
+			// Confirm maintenance, which can take a while on big repos
+			if m.gcConfirm {
+				m.gcConfirm = false
+				switch msg.String() {
+				case "y", "Y":
+					m.statusMsg = ""
+					return m, m.startCommands([]string{"git gc"})
+				case "p", "P":
+					m.statusMsg = ""
+					return m, m.startCommands([]string{"git gc", "git remote prune origin"})
+				}
+				m.statusMsg = "Maintenance cancelled"
+				return m, nil
+			}
+
+			// Branch filter input
+			if m.filteringBr {
+				switch msg.String() {
+				case "esc":
+					m.resetBranchFilter()
+					m.applyBranchFilter()
+					return m, nil
+				case "enter":
+					m.filteringBr = false
+					m.branchFilter.Blur()
+					return m, nil
+				case "up":
+					if m.branchIndex > 0 {
+						m.branchIndex--
+					}
+					return m, nil
+				case "down":
+					if m.branchIndex < len(m.branches)-1 {
+						m.branchIndex++
+					}
+					return m, nil
+				case "pgup", "pgdown":
+					m.branchIndex = jumpIndex(msg.String(), m.branchIndex, len(m.branches), m.branchPageSize())
+					return m, nil
+				}
+				var cmd tea.Cmd
+				m.branchFilter, cmd = m.branchFilter.Update(msg)
+				m.applyBranchFilter()
+				return m, cmd
+			}
+
 			switch msg.String() {
 			case "q", "esc":
 				if m.detailFocus == paneCommand && m.cmdInput.Value() != "" {
 					m.cmdInput.SetValue("")
 					return m, nil
 				}
+				if m.detailFocus == paneBranches && m.branchFilter.Value() != "" {
+					m.resetBranchFilter()
+					m.applyBranchFilter()
+					return m, nil
+				}
 				m.mode = listView
 				m.detailRepo = nil
+				m.detailStatus = ""
 				m.detailContent = ""
+				m.detailFiles = nil
+				m.fileIndex = 0
+				m.diskUsage = ""
 				m.cmdOutput = ""
 				m.branches = nil
+				m.allBranches = nil
+				m.defaultBranch = ""
+				m.resetBranchFilter()
 				m.detailFocus = paneStatus
 				return m, nil
 			case "tab":
@@ -127,6 +311,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else {
 					m.cmdInput.Blur()
 				}
+				m.viewport.SetContent(m.statusPaneContent())
 				return m, nil
 			case "shift+tab":
 				m.detailFocus = (m.detailFocus + 2) % 3
@@ -135,20 +320,154 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else {
 					m.cmdInput.Blur()
 				}
+				m.viewport.SetContent(m.statusPaneContent())
 				return m, nil
+			case "ctrl+c":
+				if m.cmdRunning {
+					if err := killCommand(m.cmdProc); err == nil {
+						m.cmdKilled = true
+					}
+					return m, nil
+				}
+				if m.confirmQuit(quitArmed, "ctrl+c") {
+					return m, nil
+				}
+				saveFavorites(m.favorites)
+				return m, tea.Quit
 			case "r":
 				if m.detailRepo != nil && m.detailFocus != paneCommand {
-					return m, tea.Batch(loadGitDetail(m.detailRepo.Path), loadBranches(m.detailRepo.Path))
+					return m, tea.Batch(loadGitDetail(m.detailRepo.Path, m.detailLogCount), loadBranches(m.detailRepo.Path))
+				}
+			case "ctrl+r":
+				if m.scanning {
+					return m, nil
+				}
+				return m, m.rescanAll()
+			case "s":
+				if m.detailRepo != nil && m.detailFocus != paneCommand {
+					m.mode = stashView
+					m.stashes = nil
+					m.stashIndex = 0
+					m.stashConfirm = ""
+					return m, loadStashes(m.detailRepo.Path)
+				}
+			case "?":
+				if m.detailFocus != paneCommand {
+					m.openHelp()
+					return m, nil
+				}
+			case "R":
+				if m.detailRepo != nil && m.detailFocus != paneCommand {
+					m.mode = reflogView
+					m.viewport.SetContent("Loading...")
+					m.viewport.GotoTop()
+					return m, loadReflog(m.detailRepo.Path, m.detailRepo.Branch)
+				}
+			case "H":
+				if m.detailRepo != nil && m.detailFocus != paneCommand {
+					return m, checkUpstream(m.detailRepo.Path)
+				}
+			case "u":
+				if m.detailRepo != nil && m.detailFocus != paneCommand {
+					m.statusMsg = "Restoring auto-stash..."
+					return m, popAutoStash(m.detailRepo.Path)
+				}
+			case "L":
+				if m.detailRepo != nil && m.detailFocus != paneCommand {
+					m.mode = commitLogView
+					m.logSearching = false
+					m.logSearch.SetValue("")
+					return m, m.reloadCommitLog("")
+				}
+			case "A":
+				if m.detailRepo != nil && m.detailFocus != paneCommand {
+					op := m.detailRepo.Operation
+					if op == "" {
+						m.statusMsg = "No merge or rebase in progress"
+						return m, nil
+					}
+					m.errorMsg = ""
+					m.abortOp = op
+					return m, nil
+				}
+			case "M":
+				if m.detailRepo != nil && m.detailFocus != paneCommand {
+					if m.cmdRunning {
+						m.statusMsg = "Wait for the running command to finish"
+						return m, nil
+					}
+					m.gcConfirm = true
+					return m, nil
+				}
+			case "z":
+				if m.detailRepo != nil && m.detailFocus != paneCommand {
+					m.statusMsg = "Measuring disk usage..."
+					return m, loadDiskUsage(m.detailRepo.Path)
 				}
 			}
 
 			switch m.detailFocus {
 			case paneStatus:
+				switch msg.String() {
+				case "up", "k":
+					if len(m.detailFiles) > 0 {
+						if m.fileIndex > 0 {
+							m.fileIndex--
+						}
+						m.viewport.SetContent(m.statusPaneContent())
+						m.scrollToFile()
+						return m, nil
+					}
+				case "down", "j":
+					if len(m.detailFiles) > 0 {
+						if m.fileIndex < len(m.detailFiles)-1 {
+							m.fileIndex++
+						}
+						m.viewport.SetContent(m.statusPaneContent())
+						m.scrollToFile()
+						return m, nil
+					}
+				case " ":
+					if len(m.detailFiles) > 0 && m.detailRepo != nil {
+						return m, toggleStage(m.detailRepo.Path, m.detailFiles[m.fileIndex])
+					}
+					return m, nil
+				case "enter":
+					if len(m.detailFiles) > 0 && m.detailRepo != nil {
+						m.diffFile = m.detailFiles[m.fileIndex].Path
+						m.mode = diffView
+						m.viewport.SetContent("Loading...")
+						m.viewport.GotoTop()
+						return m, loadFileDiff(m.detailRepo.Path, m.diffFile)
+					}
+					return m, nil
+				}
+				if msg.String() == "c" && m.detailRepo != nil {
+					staged := false
+					for _, f := range m.detailFiles {
+						if f.Staged() {
+							staged = true
+							break
+						}
+					}
+					if !staged {
+						m.statusMsg = "Nothing staged to commit"
+						return m, nil
+					}
+					m.mode = commitInputView
+					m.statusMsg = ""
+					m.errorMsg = ""
+					m.commitInput.SetValue("")
+					return m, m.commitInput.Focus()
+				}
 				var cmd tea.Cmd
 				m.viewport, cmd = m.viewport.Update(msg)
 				return m, cmd
 			case paneBranches:
 				switch msg.String() {
+				case "/":
+					m.filteringBr = true
+					return m, m.branchFilter.Focus()
 				case "up", "k":
 					if m.branchIndex > 0 {
 						m.branchIndex--
@@ -159,6 +478,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.branchIndex++
 					}
 					return m, nil
+				case "pgup", "pgdown", "home", "end", "g", "G":
+					m.branchIndex = jumpIndex(msg.String(), m.branchIndex, len(m.branches), m.branchPageSize())
+					return m, nil
 				case "enter":
 					if len(m.branches) > 0 && m.detailRepo != nil {
 						branch := m.branches[m.branchIndex]
@@ -171,14 +493,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							checkoutName = branch.RemoteName
 						}
 						m.targetBranch = branch.Name
-						if hasUncommittedChanges(m.detailRepo.Path) {
-							m.hasChanges = true
-							m.mode = actionSelectView
-							m.actionIndex = 0
-							return m, nil
-						}
-						m.statusMsg = "Switching to " + branch.Name + "..."
-						return m, switchBranch(m.detailRepo.Path, checkoutName)
+						return m, checkSwitch(m.detailRepo.Path, branch.Name, checkoutName)
 					}
 					return m, nil
 				case "x":
@@ -210,7 +525,66 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							m.statusMsg = "Branch is remote-only"
 							return m, nil
 						}
-						return m, deleteBranch(m.detailRepo.Path, branch.Name, true)
+						return m, checkUnmerged(m.detailRepo.Path, branch.Name)
+					}
+					return m, nil
+				case "n":
+					if m.detailRepo != nil {
+						m.mode = branchInputView
+						m.branchAction = "new"
+						m.statusMsg = ""
+						m.branchInput.SetValue("")
+						m.branchInput.Focus()
+						return m, textinput.Blink
+					}
+					return m, nil
+				case "b":
+					if len(m.branches) > 0 && m.detailRepo != nil {
+						branch := m.branches[m.branchIndex]
+						if branch.IsCurrent {
+							m.statusMsg = "Select the branch to rebase " + branch.Name + " onto"
+							return m, nil
+						}
+						if m.detailRepo.Detached {
+							m.statusMsg = "Cannot rebase a detached HEAD"
+							return m, nil
+						}
+						onto := branch.Name
+						if !branch.IsLocal {
+							onto = branch.RemoteName
+						}
+						m.errorMsg = ""
+						m.rebaseOnto = onto
+						return m, nil
+					}
+					return m, nil
+				case "D":
+					if len(m.branches) > 0 && m.detailRepo != nil {
+						branch := m.branches[m.branchIndex]
+						if !branch.IsRemote || branch.RemoteName == "" {
+							m.statusMsg = "Branch is not on remote"
+							return m, nil
+						}
+						m.errorMsg = ""
+						m.remoteDelete = branch.RemoteName
+						return m, nil
+					}
+					return m, nil
+				case "e":
+					if len(m.branches) > 0 && m.detailRepo != nil {
+						branch := m.branches[m.branchIndex]
+						if !branch.IsLocal {
+							m.statusMsg = "Branch is remote-only, nothing to rename locally"
+							return m, nil
+						}
+						m.mode = branchInputView
+						m.branchAction = "rename"
+						m.renameFrom = branch.Name
+						m.statusMsg = ""
+						m.branchInput.SetValue(branch.Name)
+						m.branchInput.CursorEnd()
+						m.branchInput.Focus()
+						return m, textinput.Blink
 					}
 					return m, nil
 				case "p":
@@ -228,14 +602,84 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						return m, createLocalBranch(m.detailRepo.Path, branch.Name, branch.RemoteName)
 					}
 					return m, nil
+				case "t":
+					if len(m.branches) > 0 && m.detailRepo != nil {
+						branch := m.branches[m.branchIndex]
+						if !branch.IsLocal {
+							m.statusMsg = "Branch is remote-only, create it locally with 'p'"
+							return m, nil
+						}
+						if !branch.NoUpstream {
+							m.statusMsg = branch.Name + " already tracks " + branch.RemoteName
+							return m, nil
+						}
+						upstream := "origin/" + branch.Name
+						if !branch.IsRemote || branch.RemoteName != upstream {
+							m.statusMsg = upstream + " does not exist, push the branch first"
+							return m, nil
+						}
+						m.statusMsg = "Setting upstream of " + branch.Name + "..."
+						return m, setUpstream(m.detailRepo.Path, branch.Name, upstream)
+					}
+					return m, nil
 				}
 				return m, nil
 			case paneCommand:
 				switch msg.String() {
+				case "ctrl+g":
+					m.cmdGitMode = !m.cmdGitMode
+					if m.cmdGitMode {
+						m.cmdInput.Prompt = "> git "
+						m.cmdInput.Placeholder = "Enter git command (e.g., log --oneline -5, !ls for non-git)..."
+						m.statusMsg = "git prefix enabled"
+					} else {
+						m.cmdInput.Prompt = "> "
+						m.cmdInput.Placeholder = "Enter command (e.g., git log --oneline -5)..."
+						m.statusMsg = "git prefix disabled"
+					}
+					config := loadConfig()
+					config.CommandGitPrefix = m.cmdGitMode
+					saveConfigFull(config)
+					return m, nil
+				case "up":
+					if m.historyIdx > 0 {
+						if m.historyIdx == len(m.cmdHistory) {
+							m.cmdDraft = m.cmdInput.Value()
+						}
+						m.historyIdx--
+						m.cmdInput.SetValue(m.cmdHistory[m.historyIdx])
+						m.cmdInput.CursorEnd()
+					}
+					return m, nil
+				case "down":
+					if m.historyIdx < len(m.cmdHistory) {
+						m.historyIdx++
+						if m.historyIdx == len(m.cmdHistory) {
+							m.cmdInput.SetValue(m.cmdDraft)
+						} else {
+							m.cmdInput.SetValue(m.cmdHistory[m.historyIdx])
+						}
+						m.cmdInput.CursorEnd()
+					}
+					return m, nil
 				case "enter":
 					if m.cmdInput.Value() != "" && !m.cmdRunning {
 						cmd := m.cmdInput.Value()
+						if n := len(m.cmdHistory); n == 0 || m.cmdHistory[n-1] != cmd {
+							m.cmdHistory = append(m.cmdHistory, cmd)
+							if len(m.cmdHistory) > maxHistory {
+								m.cmdHistory = m.cmdHistory[len(m.cmdHistory)-maxHistory:]
+							}
+							saveCommandHistory(m.cmdHistory)
+						}
+						m.historyIdx = len(m.cmdHistory)
+						m.cmdDraft = ""
+						if m.cmdGitMode {
+							cmd = applyGitPrefix(cmd)
+						}
 						m.cmdRunning = true
+						m.cmdGotLines = false
+						m.cmdKilled = false
 						m.cmdOutput = "Running: " + cmd + "\n\n"
 						m.cmdViewport.SetContent(m.cmdOutput)
 						return m, runCommand(m.detailRepo.Path, cmd)
@@ -249,6 +693,177 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Handle help overlay keys
+		if m.mode == helpView {
+			switch msg.String() {
+			case "?", "q", "esc":
+				m.mode = m.helpReturn
+				if m.mode == detailView {
+					m.viewport.SetContent(m.statusPaneContent())
+					m.viewport.GotoTop()
+				}
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		}
+
+		// Handle diff view keys
+		if m.mode == diffView {
+			switch msg.String() {
+			case "q", "esc":
+				m.mode = detailView
+				m.diffFile = ""
+				m.viewport.SetContent(m.statusPaneContent())
+				m.viewport.GotoTop()
+				m.scrollToFile()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		}
+
+		// Handle commit log keys
+		if m.mode == commitLogView {
+			if m.logSearching {
+				switch msg.String() {
+				case "esc":
+					m.logSearching = false
+					m.logSearch.Blur()
+					return m, nil
+				case "enter":
+					m.logSearching = false
+					m.logSearch.Blur()
+					return m, m.reloadCommitLog(strings.TrimSpace(m.logSearch.Value()))
+				}
+				var cmd tea.Cmd
+				m.logSearch, cmd = m.logSearch.Update(msg)
+				return m, cmd
+			}
+			switch msg.String() {
+			case "q", "esc":
+				if msg.String() == "esc" && m.logQuery != "" {
+					// First esc drops the search
+					m.logSearch.SetValue("")
+					return m, m.reloadCommitLog("")
+				}
+				m.mode = detailView
+				m.logCommits = nil
+				m.viewport.SetContent(m.statusPaneContent())
+				m.viewport.GotoTop()
+				return m, nil
+			case "/":
+				m.logSearching = true
+				m.logSearch.SetValue(m.logQuery)
+				m.logSearch.CursorEnd()
+				m.logSearch.Focus()
+				return m, textinput.Blink
+			case "up", "k":
+				if m.logIndex > 0 {
+					m.logIndex--
+				}
+			case "down", "j":
+				if m.logIndex < len(m.logCommits)-1 {
+					m.logIndex++
+				}
+			case "pgup", "pgdown", "home", "end", "g", "G":
+				m.logIndex = jumpIndex(msg.String(), m.logIndex, len(m.logCommits), m.commitLogHeight())
+			case "enter":
+				if m.logIndex < len(m.logCommits) && m.detailRepo != nil {
+					m.logShowHash = m.logCommits[m.logIndex].Hash
+					m.mode = commitShowView
+					m.viewport.SetContent("Loading...")
+					m.viewport.GotoTop()
+					return m, loadCommitShow(m.detailRepo.Path, m.logShowHash)
+				}
+			}
+			return m, m.loadMoreCommits()
+		}
+
+		// Handle commit view keys
+		if m.mode == commitShowView {
+			switch msg.String() {
+			case "q", "esc":
+				m.mode = commitLogView
+				m.logShowHash = ""
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		}
+
+		// Handle reflog view keys
+		if m.mode == reflogView {
+			switch msg.String() {
+			case "q", "esc":
+				m.mode = detailView
+				m.viewport.SetContent(m.statusPaneContent())
+				m.viewport.GotoTop()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		}
+
+		// Handle stash view keys
+		if m.mode == stashView {
+			if m.stashConfirm != "" {
+				switch msg.String() {
+				case "y", "enter":
+					action := m.stashConfirm
+					m.stashConfirm = ""
+					if m.detailRepo == nil || m.stashIndex >= len(m.stashes) {
+						return m, nil
+					}
+					ref := m.stashes[m.stashIndex].Ref
+					switch action {
+					case "apply":
+						m.statusMsg = "Applying " + ref + "..."
+						return m, applyStash(m.detailRepo.Path, ref, false)
+					case "pop":
+						m.statusMsg = "Popping " + ref + "..."
+						return m, applyStash(m.detailRepo.Path, ref, true)
+					case "drop":
+						m.statusMsg = "Dropping " + ref + "..."
+						return m, dropStash(m.detailRepo.Path, ref)
+					}
+				case "n", "esc", "q":
+					m.stashConfirm = ""
+				}
+				return m, nil
+			}
+			switch msg.String() {
+			case "q", "esc":
+				m.mode = detailView
+				return m, nil
+			case "up", "k":
+				if m.stashIndex > 0 {
+					m.stashIndex--
+				}
+			case "down", "j":
+				if m.stashIndex < len(m.stashes)-1 {
+					m.stashIndex++
+				}
+			case "a", "enter":
+				if len(m.stashes) > 0 {
+					m.stashConfirm = "apply"
+				}
+			case "p":
+				if len(m.stashes) > 0 {
+					m.stashConfirm = "pop"
+				}
+			case "d", "x":
+				if len(m.stashes) > 0 {
+					m.stashConfirm = "drop"
+				}
+			}
+			return m, nil
+		}
+
 		// Handle action select view keys
 		if m.mode == actionSelectView {
 			actions := []string{"Stash changes", "Discard changes", "Cancel"}
@@ -289,22 +904,44 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Handle pull preview keys
+		if m.mode == pullPreviewView {
+			switch msg.String() {
+			case "q", "esc", "n":
+				m.mode = listView
+				m.pullPreview = nil
+				m.statusMsg = "Pull cancelled"
+				return m, nil
+			case "enter", "p", "y":
+				m.mode = listView
+				if batchCmds := m.pullFromPreview(); len(batchCmds) > 0 {
+					return m, tea.Batch(batchCmds...)
+				}
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		}
+
 		// Handle error view keys
 		if m.mode == errorView {
 			switch msg.String() {
 			case "q", "esc", "enter":
-				m.errorMsg = ""
-				if m.previousMode == detailView && m.detailRepo != nil {
-					m.mode = detailView
-					return m, loadGitDetail(m.detailRepo.Path)
+				return m, m.dismissError()
+			case "r":
+				if len(m.retryPaths) == 0 || m.pulling {
+					return m, nil
 				}
-				m.mode = listView
-				m.detailRepo = nil
-				if m.savedFilter != "" {
-					m.list.SetFilterText(m.savedFilter)
-					m.savedFilter = ""
+				paths := m.retryPaths
+				if len(paths) > 1 {
+					// A batch retry shows its progress in the list
+					cmd := m.dismissError()
+					return m, tea.Batch(append(m.retryPulls(paths), cmd)...)
 				}
-				return m, nil
+				// Stay here until the pull succeeds, so a second failure just
+				// replaces the message
+				return m, tea.Batch(m.retryPulls(paths)...)
 			}
 			var cmd tea.Cmd
 			m.viewport, cmd = m.viewport.Update(msg)
@@ -317,13 +954,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "q", "esc":
 				m.mode = listView
 				return m, nil
+			case "?":
+				m.openHelp()
+				return m, nil
 			case "up", "k":
 				if m.settingsIndex > 0 {
 					m.settingsIndex--
 				}
 				return m, nil
 			case "down", "j":
-				if m.settingsIndex < 4 {
+				if m.settingsIndex < 9 {
 					m.settingsIndex++
 				}
 				return m, nil
@@ -355,6 +995,47 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.statusMsg = "Pull results screen disabled"
 					}
 					saveConfigFull(config)
+				} else if m.settingsIndex == 5 {
+					// Toggle auto-fetch on refresh
+					m.autoFetch = !m.autoFetch
+					config.AutoFetchOnRefresh = &m.autoFetch
+					if m.autoFetch {
+						m.statusMsg = "Auto-fetch on refresh enabled"
+					} else {
+						m.statusMsg = "Auto-fetch on refresh disabled (local status only)"
+					}
+					saveConfigFull(config)
+				} else if m.settingsIndex == 7 {
+					// Toggle skipping dirty repos in batch pulls
+					m.skipDirty = !m.skipDirty
+					config.BatchPullSkipDirty = &m.skipDirty
+					if m.skipDirty {
+						m.statusMsg = "Batch pulls skip repos with local changes"
+					} else {
+						m.statusMsg = "Batch pulls include repos with local changes"
+					}
+					saveConfigFull(config)
+				} else if m.settingsIndex == 8 {
+					// Toggle pulling with --rebase --autostash
+					m.pullAutostash = !m.pullAutostash
+					config.PullAutostash = m.pullAutostash
+					if m.pullAutostash {
+						m.statusMsg = "Pulls rebase and autostash local changes"
+					} else {
+						m.statusMsg = "Pulls fast-forward only"
+					}
+					saveConfigFull(config)
+				} else if m.settingsIndex == 9 {
+					// Toggle tinting repo names by activity
+					m.delegate.colorByActivity = !m.delegate.colorByActivity
+					m.list.SetDelegate(*m.delegate)
+					config.ColorByActivity = m.delegate.colorByActivity
+					if m.delegate.colorByActivity {
+						m.statusMsg = "Repo names tinted by last commit age"
+					} else {
+						m.statusMsg = "Repo names no longer tinted by activity"
+					}
+					saveConfigFull(config)
 				}
 				return m, nil
 			case "left", "h":
@@ -365,6 +1046,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					saveConfigFull(config)
 					m.statusMsg = fmt.Sprintf("Max commits: %d", m.maxCommitsPerRepo)
 				}
+				if m.settingsIndex == 6 {
+					return m, m.setAutoRefresh(-1)
+				}
 				return m, nil
 			case "right", "l":
 				if m.settingsIndex == 4 && m.maxCommitsPerRepo < 20 {
@@ -374,9 +1058,109 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					saveConfigFull(config)
 					m.statusMsg = fmt.Sprintf("Max commits: %d", m.maxCommitsPerRepo)
 				}
+				if m.settingsIndex == 6 {
+					return m, m.setAutoRefresh(1)
+				}
+				return m, nil
+			}
+			return m, nil
+		}
+
+		// Handle branch input view keys
+		if m.mode == branchInputView {
+			switch msg.String() {
+			case "esc":
+				m.mode = detailView
+				m.branchInput.SetValue("")
+				m.branchInput.Blur()
+				return m, nil
+			case "enter":
+				name := strings.TrimSpace(m.branchInput.Value())
+				if name == "" {
+					m.statusMsg = "Branch name cannot be empty"
+					return m, nil
+				}
+				if m.branchAction == "rename" && name == m.renameFrom {
+					m.mode = detailView
+					m.branchInput.Blur()
+					return m, nil
+				}
+				for _, b := range m.allBranches {
+					if b.IsLocal && b.Name == name {
+						m.statusMsg = "Branch already exists: " + name
+						return m, nil
+					}
+				}
+				m.mode = detailView
+				m.branchInput.SetValue("")
+				m.branchInput.Blur()
+				if m.detailRepo == nil {
+					return m, nil
+				}
+				if m.branchAction == "rename" {
+					m.statusMsg = "Renaming " + m.renameFrom + " to " + name + "..."
+					return m, renameBranch(m.detailRepo.Path, m.renameFrom, name)
+				}
+				m.statusMsg = "Creating branch " + name + "..."
+				return m, createBranch(m.detailRepo.Path, name)
+			}
+			var cmd tea.Cmd
+			m.branchInput, cmd = m.branchInput.Update(msg)
+			return m, cmd
+		}
+
+		// Handle commit message input keys
+		if m.mode == commitInputView {
+			switch msg.String() {
+			case "esc":
+				m.mode = detailView
+				m.commitInput.SetValue("")
+				m.commitInput.Blur()
+				return m, nil
+			case "enter":
+				message := strings.TrimSpace(m.commitInput.Value())
+				if message == "" {
+					m.statusMsg = "Commit message cannot be empty"
+					return m, nil
+				}
+				m.mode = detailView
+				m.commitInput.SetValue("")
+				m.commitInput.Blur()
+				if m.detailRepo == nil {
+					return m, nil
+				}
+				m.statusMsg = "Committing..."
+				return m, commitChanges(m.detailRepo.Path, message)
+			}
+			var cmd tea.Cmd
+			m.commitInput, cmd = m.commitInput.Update(msg)
+			return m, cmd
+		}
+
+		// Handle clone input view keys
+		if m.mode == cloneInputView {
+			switch msg.String() {
+			case "esc":
+				m.mode = listView
+				m.cloneInput.SetValue("")
+				m.cloneInput.Blur()
 				return m, nil
+			case "enter":
+				url := strings.TrimSpace(m.cloneInput.Value())
+				if url == "" {
+					m.statusMsg = "Repository URL cannot be empty"
+					return m, nil
+				}
+				m.mode = listView
+				m.cloneInput.SetValue("")
+				m.cloneInput.Blur()
+				m.statusMsg = "Cloning " + url + "..."
+				m.cloning = true
+				return m, cloneRepo(m.gitDir, url)
 			}
-			return m, nil
+			var cmd tea.Cmd
+			m.cloneInput, cmd = m.cloneInput.Update(msg)
+			return m, cmd
 		}
 
 		// Handle group input view keys
@@ -398,10 +1182,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						return m, nil
 					}
 					newGroup := Group{Name: name, Repos: []string{}}
+					current := ""
+					if m.currentGroup != nil {
+						current = m.currentGroup.Name
+						newGroup.Parent = current
+					}
 					m.groups = append(m.groups, newGroup)
-					m.groupsMap[name] = &m.groups[len(m.groups)-1]
+					// Appending may move the slice, so re-point the map and current group
+					m.groupsMap = buildGroupsMap(m.groups)
+					if current != "" {
+						m.currentGroup = m.groupsMap[current]
+					}
 					saveGroups(m.groups)
-					m.statusMsg = "Created group: " + name
+					if newGroup.Parent != "" {
+						m.statusMsg = "Created subgroup: " + m.groupPath(name)
+					} else {
+						m.statusMsg = "Created group: " + name
+					}
 				} else if m.groupAction == "rename" && m.currentGroup != nil {
 					oldName := m.currentGroup.Name
 					if name != oldName {
@@ -410,8 +1207,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							return m, nil
 						}
 						delete(m.groupsMap, oldName)
+						if m.expandedGroups[oldName] {
+							delete(m.expandedGroups, oldName)
+							m.expandedGroups[name] = true
+							m.saveExpandedGroups()
+						}
 						m.currentGroup.Name = name
 						m.groupsMap[name] = m.currentGroup
+						for i := range m.groups {
+							if m.groups[i].Parent == oldName {
+								m.groups[i].Parent = name
+							}
+						}
 						saveGroups(m.groups)
 						m.statusMsg = "Renamed group to: " + name
 					}
@@ -435,9 +1242,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "y", "enter":
 				if m.currentGroup != nil {
 					name := m.currentGroup.Name
+					parent := m.currentGroup.Parent
 					newGroups := make([]Group, 0, len(m.groups)-1)
 					for _, g := range m.groups {
 						if g.Name != name {
+							// Subgroups move up to the deleted group's parent
+							if g.Parent == name {
+								g.Parent = parent
+							}
 							newGroups = append(newGroups, g)
 						}
 					}
@@ -445,7 +1257,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					delete(m.groupsMap, name)
 					m.groupsMap = buildGroupsMap(m.groups)
 					saveGroups(m.groups)
-					m.currentGroup = nil
+					m.currentGroup = m.groupsMap[parent]
 					m.statusMsg = "Deleted group: " + name
 				}
 				m.mode = listView
@@ -460,7 +1272,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.String() {
 			case "esc":
 				m.mode = listView
-				m.selectedRepo = nil
+				m.moveRepos = nil
 				return m, nil
 			case "up", "k":
 				if m.groupIndex > 0 {
@@ -474,66 +1286,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			case "enter":
-				if m.selectedRepo == nil {
+				if len(m.moveRepos) == 0 {
 					m.mode = listView
 					return m, nil
 				}
-				repoPath := m.selectedRepo.Path
-
-				for i := range m.groups {
-					newRepos := make([]string, 0)
-					for _, p := range m.groups[i].Repos {
-						if p != repoPath {
-							newRepos = append(newRepos, p)
-						}
-					}
-					m.groups[i].Repos = newRepos
-				}
-
-				if m.groupIndex < len(m.groups) {
-					targetGroup := &m.groups[m.groupIndex]
-					targetGroup.Repos = append(targetGroup.Repos, repoPath)
-					m.statusMsg = "Moved " + m.selectedRepo.Name + " to " + targetGroup.Name
-					if targetGroup.Name == "Favorites" {
-						m.favorites[repoPath] = true
-						for i := range m.repos {
-							if m.repos[i].Path == repoPath {
-								m.repos[i].IsFavorite = true
-								break
-							}
-						}
-						saveFavorites(m.favorites)
-					}
-				} else {
-					m.statusMsg = "Removed " + m.selectedRepo.Name + " from group"
-				}
-
-				if m.favorites[repoPath] {
-					stillInFavorites := false
-					if favGroup, ok := m.groupsMap["Favorites"]; ok {
-						for _, p := range favGroup.Repos {
-							if p == repoPath {
-								stillInFavorites = true
-								break
-							}
-						}
-					}
-					if !stillInFavorites {
-						m.favorites[repoPath] = false
-						for i := range m.repos {
-							if m.repos[i].Path == repoPath {
-								m.repos[i].IsFavorite = false
-								break
-							}
-						}
-						saveFavorites(m.favorites)
-					}
-				}
-
-				saveGroups(m.groups)
-				m.groupsMap = buildGroupsMap(m.groups)
+				m.moveReposToGroup(m.moveRepos, m.groupIndex)
+				clear(m.marked)
 				m.mode = listView
-				m.selectedRepo = nil
+				m.moveRepos = nil
 				// Preserve filter text when updating list
 				filterText := ""
 				if m.list.FilterState() == list.FilterApplied {
@@ -545,7 +1305,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.updateList()
 				}
 				if filterText != "" {
-					m.list.SetFilterText(filterText)
+					m.reapplyFilter(filterText)
 				}
 				return m, nil
 			}
@@ -572,6 +1332,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.currentGroup != nil && len(m.ungroupedRepos) > 0 && m.addRepoIndex < len(m.ungroupedRepos) {
 					repo := m.ungroupedRepos[m.addRepoIndex]
 					m.currentGroup.Repos = append(m.currentGroup.Repos, repo.Path)
+					if m.currentGroup.Name == pinnedGroupName {
+						savePinnedRepos(m.currentGroup.Repos)
+					}
 					saveGroups(m.groups)
 					m.statusMsg = "Added " + repo.Name + " to " + m.currentGroup.Name
 					m.ungroupedRepos = m.getUngroupedRepos()
@@ -588,24 +1351,60 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Handle group settings view keys
+		if m.mode == groupSettingsView {
+			g, ok := m.groupsMap[m.settingsGroup]
+			if !ok {
+				m.mode = listView
+				return m, nil
+			}
+			switch msg.String() {
+			case "q", "esc":
+				m.mode = listView
+				m.settingsGroup = ""
+			case "up", "k":
+				if m.groupSetIndex > 0 {
+					m.groupSetIndex--
+				}
+			case "down", "j":
+				if m.groupSetIndex < 1 {
+					m.groupSetIndex++
+				}
+			case "enter", " ", "right", "l":
+				m.cycleGroupSetting(g, 1)
+			case "left", "h":
+				m.cycleGroupSetting(g, -1)
+			}
+			return m, nil
+		}
+
 		if m.list.FilterState() == list.Filtering {
 			break
 		}
 
-		switch msg.String() {
-		case "q", "ctrl+c":
+		switch m.keys.action(msg.String()) {
+		case "quit", "ctrl+c":
+			if m.confirmQuit(quitArmed, msg.String()) {
+				return m, nil
+			}
 			saveFavorites(m.favorites)
 			return m, tea.Quit
 
-		case "esc", "backspace":
+		case "back", "backspace":
+			if len(m.marked) > 0 && msg.String() != "backspace" {
+				clear(m.marked)
+				m.statusMsg = ""
+				return m, nil
+			}
 			if m.currentGroup != nil {
-				m.currentGroup = nil
+				// Back out one level; subgroups return to their parent
+				m.currentGroup = m.groupsMap[m.currentGroup.Parent]
 				m.updateList()
 				m.statusMsg = ""
 				return m, nil
 			}
 
-		case "f":
+		case "favorite":
 			if item, ok := m.list.SelectedItem().(Repo); ok {
 				m.favorites[item.Path] = !m.favorites[item.Path]
 				for i := range m.repos {
@@ -635,6 +1434,32 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+		case "pin":
+			if item, ok := m.list.SelectedItem().(Repo); ok {
+				pinGroup, ok := m.groupsMap[pinnedGroupName]
+				if !ok {
+					return m, nil
+				}
+				pinned := false
+				newRepos := make([]string, 0, len(pinGroup.Repos)+1)
+				for _, p := range pinGroup.Repos {
+					if p == item.Path {
+						pinned = true
+						continue
+					}
+					newRepos = append(newRepos, p)
+				}
+				if pinned {
+					m.statusMsg = "Unpinned " + item.Name
+				} else {
+					newRepos = append(newRepos, item.Path)
+					m.statusMsg = "Pinned " + item.Name
+				}
+				pinGroup.Repos = newRepos
+				savePinnedRepos(newRepos)
+				m.updateList()
+			}
+
 		case "enter":
 			if group, ok := m.list.SelectedItem().(GroupItem); ok {
 				if g, exists := m.groupsMap[group.Name]; exists {
@@ -645,37 +1470,41 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 			fallthrough
-		case "p":
+		case "pull":
 			if item, ok := m.list.SelectedItem().(Repo); ok {
 				m.pulling = true
 				m.statusMsg = "Pulling " + item.Name + "..."
 				// Capture HEAD before pull for results tracking
 				m.pendingPulls[item.Path] = getHeadCommit(item.Path)
 				m.pullResults = nil // Clear previous results
-				return m, tea.Batch(m.spinner.Tick, pullRepo(item.Path))
+				m.pullSkipped = nil
+				return m, tea.Batch(m.spinner.Tick, m.pullCmd(item.Path))
 			}
 
-		case "P":
+		case "pullFavorites":
 			// Clear previous results
 			m.pullResults = nil
+			m.pullSkipped = nil
 			m.pendingPulls = make(map[string]string)
 
 			// Inside a group: pull all repos in that group
 			if m.currentGroup != nil {
-				repos := m.getGroupRepos(m.currentGroup.Name)
-				if batchCmds := m.startPullBatch(repos, fmt.Sprintf("Pulling %d repos in %s...", len(repos), m.currentGroup.Name)); len(batchCmds) > 0 {
+				repos, dirty := m.skipDirtyRepos(m.getGroupRepos(m.currentGroup.Name))
+				if batchCmds := m.startPullBatch(repos, fmt.Sprintf("Pulling %d repos in %s%s...", len(repos), m.currentGroup.Name, dirtySkippedSuffix(dirty))); len(batchCmds) > 0 {
 					return m, tea.Batch(batchCmds...)
 				}
-				m.statusMsg = "No repos to pull in " + m.currentGroup.Name
+				m.pullSkipped = nil
+				m.statusMsg = "No repos to pull in " + m.currentGroup.Name + dirtySkippedSuffix(dirty)
 				return m, nil
 			}
 			// On homepage with a group selected: pull all repos in that group
 			if group, ok := m.list.SelectedItem().(GroupItem); ok {
-				repos := m.getGroupRepos(group.Name)
-				if batchCmds := m.startPullBatch(repos, fmt.Sprintf("Pulling %d repos in %s...", len(repos), group.Name)); len(batchCmds) > 0 {
+				repos, dirty := m.skipDirtyRepos(m.getGroupRepos(group.Name))
+				if batchCmds := m.startPullBatch(repos, fmt.Sprintf("Pulling %d repos in %s%s...", len(repos), group.Name, dirtySkippedSuffix(dirty))); len(batchCmds) > 0 {
 					return m, tea.Batch(batchCmds...)
 				}
-				m.statusMsg = "No repos to pull in " + group.Name
+				m.pullSkipped = nil
+				m.statusMsg = "No repos to pull in " + group.Name + dirtySkippedSuffix(dirty)
 				return m, nil
 			}
 			// Otherwise: pull all favorites
@@ -685,14 +1514,19 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					favRepos = append(favRepos, repo)
 				}
 			}
-			if batchCmds := m.startPullBatch(favRepos, fmt.Sprintf("Pulling %d favorites...", len(favRepos))); len(batchCmds) > 0 {
+			favRepos, dirty := m.skipDirtyRepos(favRepos)
+			if batchCmds := m.startPullBatch(favRepos, fmt.Sprintf("Pulling %d favorites%s...", len(favRepos), dirtySkippedSuffix(dirty))); len(batchCmds) > 0 {
 				return m, tea.Batch(batchCmds...)
 			}
+			if dirty > 0 {
+				m.pullSkipped = nil
+				m.statusMsg = "No favorites to pull" + dirtySkippedSuffix(dirty)
+			}
 
-		case "r":
+		case "refresh":
 			if m.currentGroup != nil {
-				// Respect fetch mode inside groups too
-				switch m.fetchMode {
+				// Respect fetch mode inside groups too, including the group's override
+				switch m.effectiveFetchMode() {
 				case FetchOnDemand:
 					// Only refresh selected repo
 					if item, ok := m.list.SelectedItem().(Repo); ok {
@@ -781,12 +1615,57 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.list.FilterState() == list.FilterApplied {
 					m.savedFilter = m.list.FilterValue()
 				}
+				m.rememberSelection()
 				m.list.SetItems([]list.Item{})
 				m.statusMsg = "Scanning..."
 				return m, tea.Batch(m.spinner.Tick, scanForRepos(m.gitDir))
 			}
 
-		case "ctrl+r":
+		case "fetch":
+			// Fetch without merging: the selected group, the current group, or all repos
+			repos := m.repos
+			scope := "all"
+			if group, ok := m.list.SelectedItem().(GroupItem); ok {
+				repos = m.getGroupRepos(group.Name)
+				scope = group.Name
+			} else if m.currentGroup != nil {
+				repos = m.getGroupRepos(m.currentGroup.Name)
+				scope = m.currentGroup.Name
+			}
+			paths := make([]string, len(repos))
+			for i, repo := range repos {
+				paths[i] = repo.Path
+			}
+			if batchCmds := m.startFetchOnlyBatch(paths, fmt.Sprintf("Fetching %d repos (%s)...", len(paths), scope)); len(batchCmds) > 0 {
+				return m, tea.Batch(batchCmds...)
+			}
+			m.statusMsg = "No repos to fetch"
+			return m, nil
+
+		case "pullPreview":
+			// Dry run: fetch the selected group, the current group, or all repos
+			// and show what pulling them would do
+			if m.pullPreview != nil {
+				// A preview finished while another view was open
+				m.openPullPreview()
+				return m, nil
+			}
+			repos := m.repos
+			scope := "all"
+			if group, ok := m.list.SelectedItem().(GroupItem); ok {
+				repos = m.getGroupRepos(group.Name)
+				scope = group.Name
+			} else if m.currentGroup != nil {
+				repos = m.getGroupRepos(m.currentGroup.Name)
+				scope = m.currentGroup.Name
+			}
+			if batchCmds := m.startPullPreview(repos, scope); len(batchCmds) > 0 {
+				return m, tea.Batch(batchCmds...)
+			}
+			m.statusMsg = "No repos to preview"
+			return m, nil
+
+		case "fullRefresh":
 			// Inside a group: refresh all repos in the group
 			if m.currentGroup != nil {
 				repos := m.getGroupRepos(m.currentGroup.Name)
@@ -801,70 +1680,119 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 			// Homepage: full rescan
-			m.scanning = true
-			m.repos = []Repo{}
-			m.forceFullFetch = true
-			if m.list.FilterState() == list.FilterApplied {
-				m.savedFilter = m.list.FilterValue()
-			}
-			m.list.SetItems([]list.Item{})
-			m.statusMsg = "Scanning all..."
-			return m, tea.Batch(m.spinner.Tick, scanForRepos(m.gitDir))
+			return m, m.rescanAll()
 
-		case "s":
+		case "gitUI":
 			if item, ok := m.list.SelectedItem().(Repo); ok {
+				c, err := buildToolCommand(m.gitUITool, item.Path)
+				if err != nil {
+					m.statusMsg = "Cannot open git UI: " + err.Error()
+					return m, nil
+				}
 				m.detailRepo = &item
-				c := exec.Command("lazygit")
+				return m, tea.ExecProcess(c, func(err error) tea.Msg {
+					return gitUIExitMsg{path: item.Path, err: err}
+				})
+			}
+
+		case "editor":
+			if item, ok := m.list.SelectedItem().(Repo); ok {
+				editor := loadConfig().GetEditor()
+				if editor == "" {
+					m.statusMsg = "No editor configured (set $EDITOR or \"editor\" in config.json)"
+					return m, nil
+				}
+				parts, err := splitCommandLine(editor)
+				if err != nil || len(parts) == 0 {
+					m.statusMsg = "Invalid editor command: " + editor
+					return m, nil
+				}
+				c := exec.Command(parts[0], append(parts[1:], item.Path)...)
 				c.Dir = item.Path
 				return m, tea.ExecProcess(c, func(err error) tea.Msg {
-					return lazygitExitMsg{path: item.Path, err: err}
+					return editorExitMsg{path: item.Path, err: err}
 				})
 			}
 
-		case "o":
+		case "web":
 			if item, ok := m.list.SelectedItem().(Repo); ok {
 				url, err := getRepoWebURL(item.Path)
 				if err != nil {
-					m.statusMsg = "No remote URL found"
+					m.statusMsg = "Cannot open in browser: " + err.Error()
+					return m, nil
+				}
+				if err := openInBrowser(url); err != nil {
+					m.statusMsg = "Failed to open browser: " + err.Error()
+					return m, nil
+				}
+				m.statusMsg = "Opened " + url
+			}
+
+		case "branchWeb":
+			if item, ok := m.list.SelectedItem().(Repo); ok {
+				repoURL, err := getRepoWebURL(item.Path)
+				if err != nil {
+					m.statusMsg = "Cannot open in browser: " + err.Error()
 					return m, nil
 				}
+				url := branchWebURL(repoURL, item.Branch)
 				if err := openInBrowser(url); err != nil {
-					m.statusMsg = "Failed to open browser"
+					m.statusMsg = "Failed to open browser: " + err.Error()
 					return m, nil
 				}
 				m.statusMsg = "Opened " + url
 			}
 
-		case "d":
+		case "reveal":
+			if item, ok := m.list.SelectedItem().(Repo); ok {
+				if err := openInFileManager(item.Path); err != nil {
+					m.statusMsg = "Failed to open file manager: " + err.Error()
+					return m, nil
+				}
+				m.statusMsg = "Opened " + item.Name + " in file manager"
+			}
+
+		case "details":
 			if item, ok := m.list.SelectedItem().(Repo); ok {
 				m.mode = detailView
 				m.detailRepo = &item
+				m.detailStatus = ""
 				m.detailContent = "Loading..."
+				m.detailFiles = nil
+				m.fileIndex = 0
+				m.diskUsage = ""
 				m.viewport.SetContent(m.detailContent)
 				m.detailFocus = paneStatus
 				m.cmdOutput = ""
 				m.cmdInput.SetValue("")
 				m.cmdInput.Blur()
 				m.branches = []BranchInfo{}
+				m.allBranches = nil
+				m.defaultBranch = ""
 				m.branchIndex = 0
-				return m, tea.Batch(loadGitDetail(item.Path), loadBranches(item.Path))
+				m.resetBranchFilter()
+				return m, tea.Batch(loadGitDetail(item.Path, m.detailLogCount), loadBranches(item.Path))
 			}
 
-		case "c":
+		case "configure":
 			m.mode = configView
 			m.dirInput.SetValue(m.gitDir)
 			m.dirInput.Focus()
 			return m, textinput.Blink
 
-		case "g":
+		case "goto":
 			if item, ok := m.list.SelectedItem().(Repo); ok {
+				if m.confirmQuit(quitArmed, msg.String()) {
+					return m, nil
+				}
 				m.gotoPath = item.Path
 				saveFavorites(m.favorites)
 				return m, tea.Quit
 			}
 
-		case "1":
+		case "filterDirty":
 			m.filterDirty = !m.filterDirty
+			m.saveFilters()
 			m.updateList()
 			if m.filterDirty {
 				m.statusMsg = "Filter: showing repos with local changes"
@@ -874,8 +1802,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.statusMsg = "Filter cleared"
 			}
 
-		case "2":
+		case "filterBehind":
 			m.filterBehind = !m.filterBehind
+			m.saveFilters()
 			m.updateList()
 			if m.filterBehind {
 				m.statusMsg = "Filter: showing repos behind remote"
@@ -885,45 +1814,188 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.statusMsg = "Filter cleared"
 			}
 
-		case "0":
+		case "filterDetached":
+			m.filterDetached = !m.filterDetached
+			m.saveFilters()
+			m.updateList()
+			if m.filterDetached {
+				m.statusMsg = "Filter: showing repos with a detached HEAD"
+			} else {
+				m.statusMsg = "Detached HEAD filter cleared"
+			}
+
+		case "filterConflict":
+			m.filterConflict = !m.filterConflict
+			m.saveFilters()
+			m.updateList()
+			if m.filterConflict {
+				m.statusMsg = "Filter: showing repos with merge conflicts"
+			} else {
+				m.statusMsg = "Conflict filter cleared"
+			}
+
+		case "hideClean":
+			m.hideClean = !m.hideClean
+			m.saveFilters()
+			m.updateList()
+			if m.hideClean {
+				m.statusMsg = "Filter: hiding clean repos"
+			} else {
+				m.statusMsg = "Showing clean repos again"
+			}
+
+		case "sort":
+			m.sortMode = (m.sortMode + 1) % sortModeCount
+			config := loadConfig()
+			config.SortMode = m.sortMode
+			saveConfigFull(config)
+			filterText := ""
+			if m.list.FilterState() == list.FilterApplied {
+				filterText = m.list.FilterValue()
+			}
+			if filterText != "" && m.currentGroup == nil {
+				m.updateListFlattened()
+			} else {
+				m.updateList()
+			}
+			if filterText != "" {
+				m.reapplyFilter(filterText)
+			}
+			m.statusMsg = "Sort: by " + m.sortMode.String()
+
+		case "clearFilters":
 			m.filterDirty = false
 			m.filterBehind = false
+			m.filterDetached = false
+			m.filterConflict = false
+			m.hideClean = false
+			m.saveFilters()
 			m.updateList()
 			m.statusMsg = "Filters cleared"
 
-		case "A":
+		case "pullBehind":
 			// Clear previous results
 			m.pullResults = nil
+			m.pullSkipped = nil
 			m.pendingPulls = make(map[string]string)
 
 			filtered := m.getFilteredRepos()
+			inFilter := make(map[string]bool)
 			var behindRepos []Repo
 			for _, repo := range filtered {
+				inFilter[repo.Path] = true
 				if repo.BehindCount > 0 {
 					behindRepos = append(behindRepos, repo)
+				} else {
+					m.pullSkipped = append(m.pullSkipped, SkippedRepo{RepoName: repo.Name, Reason: pullSkipReason(repo)})
+				}
+			}
+			for _, repo := range m.repos {
+				if !inFilter[repo.Path] {
+					m.pullSkipped = append(m.pullSkipped, SkippedRepo{RepoName: repo.Name, Reason: "excluded by filter"})
 				}
 			}
-			if batchCmds := m.startPullBatch(behindRepos, fmt.Sprintf("Pulling %d repos behind remote...", len(behindRepos))); len(batchCmds) > 0 {
+			behindRepos, dirty := m.skipDirtyRepos(behindRepos)
+			if batchCmds := m.startPullBatch(behindRepos, fmt.Sprintf("Pulling %d repos behind remote%s...", len(behindRepos), dirtySkippedSuffix(dirty))); len(batchCmds) > 0 {
 				return m, tea.Batch(batchCmds...)
 			} else {
-				m.statusMsg = "No repos behind remote to pull"
+				m.pullSkipped = nil
+				m.statusMsg = "No repos behind remote to pull" + dirtySkippedSuffix(dirty)
+			}
+
+		case "pullAll":
+			// Clear previous results
+			m.pullResults = nil
+			m.pullSkipped = nil
+			m.pendingPulls = make(map[string]string)
+
+			repos := m.repos
+			scope := "all"
+			if m.currentGroup != nil {
+				repos = m.getGroupRepos(m.currentGroup.Name)
+				scope = m.currentGroup.Name
+			}
+			toPull, dirty := m.skipDirtyRepos(repos)
+			var pullable []Repo
+			for _, repo := range toPull {
+				if repo.Status != StatusUnknown && !repo.HasUpstream {
+					m.pullSkipped = append(m.pullSkipped, SkippedRepo{RepoName: repo.Name, Reason: "no upstream"})
+					continue
+				}
+				pullable = append(pullable, repo)
+			}
+			if batchCmds := m.startPullBatch(pullable, fmt.Sprintf("Pulling %d repos (%s)%s...", len(pullable), scope, dirtySkippedSuffix(dirty))); len(batchCmds) > 0 {
+				return m, tea.Batch(batchCmds...)
+			}
+			m.pullSkipped = nil
+			m.statusMsg = "No repos to pull" + dirtySkippedSuffix(dirty)
+
+		case "help":
+			m.openHelp()
+			return m, nil
+
+		case "autoGroup":
+			if m.currentGroup != nil {
+				// Only applies at the top level; let the list have the key
+				break
+			}
+			if m.hasAutoGroups() {
+				removed := m.undoAutoGroups()
+				m.updateList()
+				m.statusMsg = fmt.Sprintf("Undid auto-grouping (%d repos ungrouped)", removed)
+				return m, nil
+			}
+			paths := make([]string, len(m.repos))
+			for i, repo := range m.repos {
+				paths[i] = repo.Path
+			}
+			m.statusMsg = "Grouping repos by remote host..."
+			return m, loadRemoteHosts(paths)
+
+		case "copyPath":
+			if item, ok := m.list.SelectedItem().(Repo); ok {
+				if err := copyToClipboard(item.Path); err != nil {
+					m.statusMsg = "Copy failed: " + err.Error()
+				} else {
+					m.statusMsg = "Copied " + item.Path
+				}
+			}
+			return m, nil
+
+		case "trust":
+			if item, ok := m.list.SelectedItem().(Repo); ok {
+				if !isDubiousOwnership(item.StatusErr) {
+					m.statusMsg = item.Name + " is not blocked by git's ownership check"
+					return m, nil
+				}
+				m.statusMsg = "Adding " + item.Path + " to safe.directory..."
+				return m, addSafeDirectory(item.Path)
 			}
+			return m, nil
+
+		case "clone":
+			m.mode = cloneInputView
+			m.statusMsg = ""
+			m.cloneInput.SetValue("")
+			return m, m.cloneInput.Focus()
 
-		case "S":
+		case "settings":
 			m.mode = settingsView
 			m.settingsIndex = int(m.fetchMode)
 			return m, nil
 
-		case "n":
-			if m.currentGroup == nil {
-				m.mode = groupInputView
-				m.groupAction = "new"
-				m.groupInput.SetValue("")
-				m.groupInput.Focus()
-				return m, textinput.Blink
+		case "newGroup":
+			if m.currentGroup != nil && m.currentGroup.IsBuiltIn {
+				m.statusMsg = "Cannot create subgroups in built-in group"
+				return m, nil
 			}
+			m.mode = groupInputView
+			m.groupAction = "new"
+			m.groupInput.SetValue("")
+			m.groupInput.Focus()
+			return m, textinput.Blink
 
-		case "e":
+		case "renameGroup":
 			if m.currentGroup != nil && !m.currentGroup.IsBuiltIn {
 				m.mode = groupInputView
 				m.groupAction = "rename"
@@ -943,7 +2015,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-		case "x":
+		case "remove":
+			if group, ok := m.list.SelectedItem().(GroupItem); ok {
+				if g, exists := m.groupsMap[group.Name]; exists {
+					if g.IsBuiltIn {
+						m.statusMsg = "Cannot delete built-in group"
+						return m, nil
+					}
+					m.currentGroup = g
+					m.mode = groupDeleteView
+				}
+				return m, nil
+			}
 			if m.currentGroup != nil {
 				if item, ok := m.list.SelectedItem().(Repo); ok {
 					newRepos := make([]string, 0)
@@ -962,6 +2045,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							}
 						}
 						saveFavorites(m.favorites)
+					} else if m.currentGroup.Name == pinnedGroupName {
+						savePinnedRepos(newRepos)
 					}
 					saveGroups(m.groups)
 					m.statusMsg = "Removed " + item.Name + " from " + m.currentGroup.Name
@@ -969,33 +2054,94 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			}
+			return m, nil
+
+		case "addRepos":
+			if m.currentGroup != nil {
+				m.ungroupedRepos = m.getUngroupedRepos()
+				if len(m.ungroupedRepos) == 0 {
+					m.statusMsg = "No ungrouped repos to add"
+					return m, nil
+				}
+				m.addRepoIndex = 0
+				m.mode = groupAddReposView
+				return m, nil
+			}
+
+		case "K", "J", "shift+up", "shift+down":
+			// Move the selected group up or down among its siblings
 			if group, ok := m.list.SelectedItem().(GroupItem); ok {
-				if g, exists := m.groupsMap[group.Name]; exists {
-					if g.IsBuiltIn {
-						m.statusMsg = "Cannot delete built-in group"
-						return m, nil
+				delta := 1
+				if key := msg.String(); key == "K" || key == "shift+up" {
+					delta = -1
+				}
+				if g, exists := m.groupsMap[group.Name]; exists && g.IsBuiltIn {
+					m.statusMsg = "Cannot move built-in group"
+				} else if m.moveGroup(group.Name, delta) {
+					m.updateList()
+					for i, item := range m.list.Items() {
+						if gi, ok := item.(GroupItem); ok && gi.Name == group.Name {
+							m.list.Select(i)
+							break
+						}
 					}
-					m.currentGroup = g
-					m.mode = groupDeleteView
 				}
 			}
 			return m, nil
 
-		case "a":
-			if m.currentGroup != nil {
-				m.ungroupedRepos = m.getUngroupedRepos()
-				if len(m.ungroupedRepos) == 0 {
-					m.statusMsg = "No ungrouped repos to add"
-					return m, nil
-				}
-				m.addRepoIndex = 0
-				m.mode = groupAddReposView
+		case "expandGroup":
+			// Expand or collapse the selected group inline on the homepage
+			if group, ok := m.list.SelectedItem().(GroupItem); ok && m.currentGroup == nil {
+				m.toggleGroupExpanded(group.Name)
+				m.updateList()
+			}
+			return m, nil
+
+		case "groupSettings":
+			// Edit the pull strategy and fetch mode of the selected or current group
+			name := ""
+			if group, ok := m.list.SelectedItem().(GroupItem); ok {
+				name = group.Name
+			} else if m.currentGroup != nil {
+				name = m.currentGroup.Name
+			}
+			if g, ok := m.groupsMap[name]; ok {
+				if g.IsBuiltIn {
+					m.statusMsg = "Built-in groups use the global settings"
+					return m, nil
+				}
+				m.settingsGroup = name
+				m.groupSetIndex = 0
+				m.statusMsg = ""
+				m.mode = groupSettingsView
+			}
+			return m, nil
+
+		case " ":
+			// Toggle the repo for a bulk move with m
+			if item, ok := m.list.SelectedItem().(Repo); ok {
+				if m.marked[item.Path] {
+					delete(m.marked, item.Path)
+				} else {
+					m.marked[item.Path] = true
+				}
+				m.list.CursorDown()
+				m.statusMsg = fmt.Sprintf("%d selected • %s", len(m.marked), m.keys.hint("{moveRepo}: move to group • {back}: clear"))
+				if len(m.marked) == 0 {
+					m.statusMsg = ""
+				}
+			}
+			return m, nil
+
+		case "moveRepo":
+			if repos := m.markedRepos(); len(repos) > 0 {
+				m.moveRepos = repos
+				m.groupIndex = 0
+				m.mode = groupSelectView
 				return m, nil
 			}
-
-		case "m":
 			if item, ok := m.list.SelectedItem().(Repo); ok {
-				m.selectedRepo = &item
+				m.moveRepos = []Repo{item}
 				m.groupIndex = 0
 				m.mode = groupSelectView
 				return m, nil
@@ -1003,9 +2149,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case spinner.TickMsg:
-		if m.scanning || m.pulling {
+		if m.scanning || m.pulling || m.batchOp == "fetch" {
 			var cmd tea.Cmd
 			m.spinner, cmd = m.spinner.Update(msg)
+			*m.spinnerFrame = m.spinner.View()
 			cmds = append(cmds, cmd)
 		}
 
@@ -1015,15 +2162,34 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 
 	case repoFoundMsg:
+		if msg.err != nil {
+			m.scanning = false
+			m.repos = nil
+			m.updateList()
+			m.statusMsg = ""
+			m.showError(fmt.Sprintf("Could not scan %s:\n\n%v\n\nPress %s to configure the git directory.", m.gitDir, msg.err, m.keys.key("configure")))
+			m.previousMode = listView
+			break
+		}
 		for i := range msg.repos {
 			msg.repos[i].IsFavorite = m.favorites[msg.repos[i].Path]
+			msg.repos[i].LastPulled = m.lastPulled[msg.repos[i].Path]
 		}
 		m.repos = msg.repos
 		m.scanning = false
 		m.statusMsg = fmt.Sprintf("Found %d repositories", len(m.repos))
+		if len(m.repos) == 0 {
+			m.statusMsg = "No git repositories found in " + m.gitDir + m.keys.hint(" ({configure}: change directory)")
+		}
+		if msg.unreadable > 0 {
+			m.statusMsg += fmt.Sprintf(" (%d unreadable directories skipped)", msg.unreadable)
+		}
+		if len(msg.invalidPinned) > 0 {
+			m.statusMsg += " (pinned, not a git repo: " + strings.Join(msg.invalidPinned, ", ") + ")"
+		}
 		m.updateList()
 		if m.savedFilter != "" {
-			m.list.SetFilterText(m.savedFilter)
+			m.reapplyFilter(m.savedFilter)
 			m.savedFilter = ""
 		}
 
@@ -1044,6 +2210,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 			default:
+				if m.autoFetchLimit > 0 && len(m.repos) > m.autoFetchLimit {
+					// Too many repos to fetch on startup, switch to on-demand for this session
+					m.fetchMode = FetchOnDemand
+					m.statusMsg = fmt.Sprintf("Found %d repositories (over auto-fetch limit of %d) - switched to on-demand, ctrl+r fetches all", len(m.repos), m.autoFetchLimit)
+					break
+				}
 				for _, repo := range m.repos {
 					fetchPaths = append(fetchPaths, repo.Path)
 				}
@@ -1053,19 +2225,59 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, batchCmds...)
 		}
 
+	case fetchCompleteMsg:
+		if msg.failed && m.fetchingOnly() {
+			m.fetchFailed++
+		}
+		cmds = append(cmds, checkGitStatus(msg.path))
+
+	case autoRefreshMsg:
+		if msg.gen != m.autoRefreshGen {
+			// Interval changed since this tick was scheduled
+			break
+		}
+		if !m.autoRefreshBusy() {
+			for _, path := range m.autoRefreshPaths() {
+				cmds = append(cmds, checkGitStatus(path))
+			}
+		}
+		cmds = append(cmds, scheduleAutoRefresh(m.autoRefresh, m.autoRefreshGen))
+
 	case statusUpdatedMsg:
+		// Only checks the batch marked as loading count toward its progress,
+		// not stray ones such as an auto-refresh started before it
+		inBatch := m.statusLoading[msg.path]
+		delete(m.statusLoading, msg.path)
+		if m.detailRepo != nil && m.detailRepo.Path == msg.path {
+			m.detailRepo.StatusErr = msg.errDetail
+			m.detailRepo.LatestTag = msg.latestTag
+			m.detailRepo.TagDistance = msg.tagDistance
+			m.detailRepo.Operation = msg.operation
+		}
 		for i := range m.repos {
 			if m.repos[i].Path == msg.path {
 				m.repos[i].Status = msg.status
 				m.repos[i].StatusText = msg.text
+				m.repos[i].ChangedCount = msg.changed
 				m.repos[i].Branch = msg.branch
 				m.repos[i].BehindCount = msg.behindCount
+				m.repos[i].StashCount = msg.stashCount
+				m.repos[i].HasUpstream = msg.hasUpstream
+				m.repos[i].UpstreamAge = msg.upstreamAge
+				m.repos[i].LastCommitTime = msg.lastCommitTime
+				m.repos[i].LastCommit = msg.lastCommit
+				m.repos[i].LastSubject = msg.lastSubject
+				m.repos[i].Operation = msg.operation
+				m.repos[i].Detached = msg.detached
+				m.repos[i].StatusErr = msg.errDetail
+				m.repos[i].LatestTag = msg.latestTag
+				m.repos[i].TagDistance = msg.tagDistance
 				break
 			}
 		}
 
 		// Update progress if in batch fetch operation
-		if m.batchOp == "fetch" && m.progressTotal > 0 {
+		if m.batchOp == "fetch" && m.progressTotal > 0 && inBatch {
 			m.progressDone++
 			percent := float64(m.progressDone) / float64(m.progressTotal)
 			cmds = append(cmds, m.progress.SetPercent(percent))
@@ -1073,14 +2285,26 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Dequeue next fetch operation
 			if m.fetchQueue != nil {
 				if next, ok := m.fetchQueue.Next(); ok {
-					cmds = append(cmds, checkGitStatus(next))
+					cmds = append(cmds, m.refreshRepo(next))
 				}
 			}
 
 			// Check if batch is complete
 			if m.progressDone >= m.progressTotal {
 				m.batchOp = ""
-				m.statusMsg = fmt.Sprintf("Refreshed %d repos", m.progressTotal)
+				elapsed := formatElapsed(time.Since(m.batchStart))
+				m.statusMsg = fmt.Sprintf("Refreshed %d repos in %s", m.progressTotal, elapsed)
+				if m.fetchingOnly() {
+					m.statusMsg = fmt.Sprintf("Fetched %d repos in %s", m.progressTotal, elapsed)
+					if m.fetchFailed > 0 {
+						m.statusMsg += fmt.Sprintf(" (%d failed)", m.fetchFailed)
+					}
+				}
+				if m.previewRepos != nil {
+					m.statusMsg = "Checking what a pull would do..."
+					cmds = append(cmds, previewPulls(m.previewRepos))
+					m.previewRepos = nil
+				}
 				m.progressTotal = 0
 				m.progressDone = 0
 				m.fetchQueue = nil
@@ -1096,7 +2320,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.updateList()
 		if filterText != "" {
-			m.list.SetFilterText(filterText)
+			m.reapplyFilter(filterText)
 		}
 
 	case pullCompleteMsg:
@@ -1106,9 +2330,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				repoName = m.repos[i].Name
 				if msg.err != nil {
 					m.repos[i].PullResult = "error"
+				} else if msg.fetchOnly {
+					m.repos[i].PullResult = msg.shortResult
+					m.errorMsg = ""
 				} else {
 					m.repos[i].PullResult = msg.shortResult
 					m.errorMsg = ""
+					m.repos[i].LastPulled = time.Now()
+					m.lastPulled[msg.path] = m.repos[i].LastPulled
+					saveLastPulled(m.lastPulled)
 				}
 				break
 			}
@@ -1118,7 +2348,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if oldHead, ok := m.pendingPulls[msg.path]; ok {
 			delete(m.pendingPulls, msg.path)
 
-			if msg.err == nil && !strings.Contains(msg.result, "Already up to date") {
+			if msg.err == nil && msg.fetchOnly {
+				if m.batchOp == "pull" {
+					m.pullSkipped = append(m.pullSkipped, SkippedRepo{RepoName: repoName, Reason: "fetched only (group pulls fetch-only)"})
+				}
+			} else if pulledNewCommits(msg) {
+				if hook := m.getPostPullHook(msg.path); hook != "" {
+					cmds = append(cmds, runPostPullHook(msg.path, hook))
+				}
+
 				newHead := getHeadCommit(msg.path)
 				commits := getCommitsBetween(msg.path, oldHead, newHead)
 				filesChanged := getFilesChangedCount(msg.path, oldHead, newHead)
@@ -1130,8 +2368,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						Commits:      commits,
 						FilesChanged: filesChanged,
 						Updated:      true,
+						Elapsed:      msg.elapsed,
 					})
 				}
+			} else if msg.err == nil && m.batchOp == "pull" {
+				m.pullSkipped = append(m.pullSkipped, SkippedRepo{RepoName: repoName, Reason: "already up to date"})
 			}
 		}
 
@@ -1144,7 +2385,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Dequeue next pull operation
 			if m.pullQueue != nil {
 				if next, ok := m.pullQueue.Next(); ok {
-					cmds = append(cmds, pullRepo(next))
+					cmds = append(cmds, m.pullCmd(next))
 				}
 			}
 		}
@@ -1152,15 +2393,31 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Check if all pulls are done
 		allDone := len(m.pendingPulls) == 0
 
-		if msg.err != nil {
+		// In a batch, failures are collected for the results screen instead of
+		// interrupting it, including a pull whose autostash conflicted
+		batchFailure := msg.err != nil && m.batchOp == "pull"
+		if batchFailure {
+			firstLine, _, _ := strings.Cut(msg.result, "\n")
+			if msg.conflicted {
+				firstLine = "pulled, but reapplying the autostash conflicted (changes kept in the stash)"
+			}
+			m.pullFailed = append(m.pullFailed, FailedPull{RepoPath: msg.path, RepoName: repoName, Err: firstLine, Conflicted: msg.conflicted})
+		}
+
+		if msg.err != nil && !batchFailure {
 			m.statusMsg = ""
-			m.errorMsg = fmt.Sprintf("Pull failed for %s:\n\n%s", repoName, msg.result)
-			m.previousMode = m.mode
-			if m.list.FilterState() == list.FilterApplied {
-				m.savedFilter = m.list.FilterValue()
+			errMsg := fmt.Sprintf("Pull failed for %s:\n\n%s", repoName, msg.result)
+			if isDubiousOwnership(msg.result) {
+				errMsg += m.keys.hint("\n\nThe repo is owned by another user. Press {trust} on it in the list to trust it (git config --global --add safe.directory).")
+			}
+			if msg.conflicted {
+				errMsg = fmt.Sprintf("Pulled %s, but reapplying your stashed changes conflicted.\nResolve the conflicts in the working tree; the changes are also kept in the stash.\n\n%s", repoName, msg.result)
 			}
-			m.mode = errorView
-			m.viewport.SetContent(m.errorMsg)
+			m.retryPaths = nil
+			if !msg.conflicted {
+				m.retryPaths = []string{msg.path}
+			}
+			m.showError(errMsg)
 			m.pulling = !allDone
 		} else {
 			filterText := ""
@@ -1169,7 +2426,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.updateList()
 			if filterText != "" {
-				m.list.SetFilterText(filterText)
+				m.reapplyFilter(filterText)
+			}
+
+			// A successful retry from the error view returns to where it was
+			if msg.err == nil && m.mode == errorView && len(m.retryPaths) == 1 && m.retryPaths[0] == msg.path {
+				cmds = append(cmds, m.dismissError())
 			}
 
 			if allDone {
@@ -1177,42 +2439,119 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.batchOp = ""
 				m.pullQueue = nil
 				// Show results screen if enabled and there are results
-				if m.showPullResults && len(m.pullResults) > 0 {
+				if m.showPullResults && (len(m.pullResults) > 0 || len(m.pullSkipped) > 0 || len(m.pullFailed) > 0) {
 					m.mode = pullResultsView
 					m.pullResultsCursor.Reset()
 					m.filesCache = make(map[string][]FileChange)
 					m.statusMsg = ""
+				} else if len(m.pullFailed) > 0 {
+					m.showPullFailures()
+					m.pullFailed = nil
+				} else if m.progressTotal > 0 {
+					m.statusMsg = fmt.Sprintf("Pulled %d repos in %s", m.progressTotal, formatElapsed(time.Since(m.batchStart)))
 				} else {
-					m.statusMsg = fmt.Sprintf("Pulled %d repos", m.progressTotal)
+					m.statusMsg = fmt.Sprintf("Pulled %s in %s: %s", repoName, formatElapsed(msg.elapsed), msg.shortResult)
 				}
 				m.progressTotal = 0
 				m.progressDone = 0
+			} else if batchFailure && msg.conflicted {
+				m.statusMsg = "Stash conflict in " + repoName
+			} else if batchFailure {
+				m.statusMsg = "Pull failed for " + repoName
 			} else {
-				m.statusMsg = fmt.Sprintf("Pulled %s: %s", repoName, msg.shortResult)
+				m.statusMsg = fmt.Sprintf("Pulled %s in %s: %s", repoName, formatElapsed(msg.elapsed), msg.shortResult)
 			}
 		}
 		cmds = append(cmds, checkGitStatus(msg.path))
 
+	case pullPreviewMsg:
+		for i := range msg.entries {
+			msg.entries[i].Strategy = m.effectivePullStrategy(msg.entries[i].RepoPath)
+		}
+		m.pullPreview = msg.entries
+		// Don't pull the user out of another view; keep the preview for later
+		if m.mode != listView {
+			m.statusMsg = m.keys.hint("Pull preview ready, press {pullPreview} on the repo list to view it")
+			break
+		}
+		m.openPullPreview()
+
 	case detailLoadedMsg:
-		if m.mode == detailView && m.detailRepo != nil && m.detailRepo.Path == msg.path {
+		if m.detailRepo != nil && m.detailRepo.Path == msg.path {
+			m.detailStatus = msg.status
 			m.detailContent = msg.content
-			m.viewport.SetContent(m.detailContent)
+			m.detailFiles = msg.files
+			if m.fileIndex >= len(m.detailFiles) {
+				m.fileIndex = max(len(m.detailFiles)-1, 0)
+			}
+			if m.mode == detailView {
+				m.viewport.SetContent(m.statusPaneContent())
+			}
+		}
+
+	case diskUsageMsg:
+		if m.detailRepo != nil && m.detailRepo.Path == msg.path {
+			if msg.err != nil {
+				m.statusMsg = ""
+				m.errorMsg = "Could not measure disk usage: " + msg.err.Error()
+				break
+			}
+			m.statusMsg = ""
+			m.diskUsage = "worktree " + formatBytes(msg.worktree) + " • objects " + formatBytes(msg.objects)
+			if m.mode == detailView {
+				m.viewport.SetContent(m.statusPaneContent())
+			}
+		}
+
+	case safeDirectoryAddedMsg:
+		if msg.err != nil {
+			m.statusMsg = ""
+			m.errorMsg = "Could not add safe.directory: " + msg.err.Error()
+			break
+		}
+		m.errorMsg = ""
+		m.statusMsg = "Trusted " + msg.path + " (safe.directory), re-checking..."
+		cmds = append(cmds, checkGitStatus(msg.path))
+
+	case remoteHostsLoadedMsg:
+		assigned := m.autoGroupByHost(msg.hosts)
+		if assigned == 0 {
+			m.statusMsg = "No ungrouped repos with a remote host"
+		} else {
+			m.updateList()
+			m.statusMsg = fmt.Sprintf("Auto-grouped %d repos by host (press %s again to undo)", assigned, m.keys.key("autoGroup"))
+		}
+
+	case fileDiffLoadedMsg:
+		if m.mode == diffView && m.detailRepo != nil && m.detailRepo.Path == msg.path && m.diffFile == msg.file {
+			m.viewport.SetContent(msg.content)
+		}
+
+	case reflogLoadedMsg:
+		if m.mode == reflogView && m.detailRepo != nil && m.detailRepo.Path == msg.path {
+			m.viewport.SetContent(msg.content)
 		}
 
 	case branchesLoadedMsg:
 		if m.detailRepo != nil && m.detailRepo.Path == msg.path {
+			m.allBranches = msg.branches
 			m.branches = msg.branches
+			m.defaultBranch = msg.defaultBranch
 			for i, b := range m.branches {
 				if b.IsCurrent {
 					m.branchIndex = i
 					break
 				}
 			}
+			m.applyBranchFilter()
 		}
 
 	case branchDeleteMsg:
 		if msg.success {
 			m.statusMsg = "Deleted branch: " + msg.branch
+			if msg.tip != "" {
+				m.statusMsg += " (was " + msg.tip + ", restore with: git branch " + msg.branch + " " + msg.tip + ")"
+			}
 			if m.detailRepo != nil {
 				cmds = append(cmds, loadBranches(m.detailRepo.Path))
 			}
@@ -1220,19 +2559,173 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.errorMsg = "Delete failed: " + msg.err
 		}
 
+	case unmergedCheckedMsg:
+		if m.detailRepo == nil || m.detailRepo.Path != msg.path {
+			break
+		}
+		if msg.err != nil {
+			m.errorMsg = "Cannot check whether " + msg.branch + " is merged: " + msg.err.Error()
+			break
+		}
+		if msg.unmerged > 0 {
+			m.errorMsg = ""
+			m.forceDelete = msg.branch
+			m.forceLoss = msg.unmerged
+			break
+		}
+		cmds = append(cmds, deleteBranch(msg.path, msg.branch, true))
+
+	case upstreamCheckedMsg:
+		if m.mode != detailView || m.detailRepo == nil || m.detailRepo.Path != msg.path {
+			break
+		}
+		if msg.err != nil {
+			m.showError("Cannot reset to upstream:\n\n" + msg.err.Error())
+			break
+		}
+		// Untracked files survive a hard reset
+		changes := 0
+		for _, f := range m.detailFiles {
+			if !f.Untracked() {
+				changes++
+			}
+		}
+		m.resetUpstream = msg.upstream
+		m.resetLoss = fmt.Sprintf("%d local commits and %d uncommitted changes", msg.ahead, changes)
+		m.resetConfirm = 1
+
+	case resetHardMsg:
+		if msg.success {
+			m.statusMsg = "Reset to " + m.resetUpstream
+			m.errorMsg = ""
+		} else {
+			m.statusMsg = ""
+			m.showError("Reset to upstream failed:\n\n" + msg.err)
+		}
+		cmds = append(cmds, loadGitDetail(msg.path, m.detailLogCount), loadBranches(msg.path), checkGitStatus(msg.path))
+
+	case remoteBranchDeleteMsg:
+		if msg.success {
+			m.statusMsg = "Deleted " + msg.remote + "/" + msg.branch + " on remote"
+			m.errorMsg = ""
+			if m.detailRepo != nil {
+				cmds = append(cmds, loadBranches(m.detailRepo.Path))
+			}
+		} else {
+			m.statusMsg = ""
+			m.showError(fmt.Sprintf("Remote delete of %s/%s failed:\n\n%s", msg.remote, msg.branch, msg.err))
+		}
+
 	case branchCreateMsg:
 		if msg.success {
 			m.statusMsg = "Created local branch: " + msg.branch
+			m.errorMsg = ""
+			if msg.checkout {
+				m.statusMsg = "Created and switched to " + msg.branch
+				if m.detailRepo != nil && m.detailRepo.Path == msg.path {
+					m.detailRepo.Branch = msg.branch
+				}
+				for i := range m.repos {
+					if m.repos[i].Path == msg.path {
+						m.repos[i].Branch = msg.branch
+						break
+					}
+				}
+				cmds = append(cmds, loadGitDetail(msg.path, m.detailLogCount), checkGitStatus(msg.path))
+			}
 			if m.detailRepo != nil {
 				cmds = append(cmds, loadBranches(m.detailRepo.Path))
 			}
 		} else {
+			m.statusMsg = ""
 			m.errorMsg = "Create failed: " + msg.err
 		}
 
+	case stageToggledMsg:
+		if msg.err != "" {
+			m.errorMsg = "Could not update " + msg.file + ": " + msg.err
+		} else {
+			m.errorMsg = ""
+			if msg.staged {
+				m.statusMsg = "Staged " + msg.file
+			} else {
+				m.statusMsg = "Unstaged " + msg.file
+			}
+		}
+		cmds = append(cmds, loadGitDetail(msg.path, m.detailLogCount), checkGitStatus(msg.path))
+
+	case commitMsg:
+		if msg.success {
+			// First line of git's output, e.g. "[main 1a2b3c4] Fix typo"
+			summary, _, _ := strings.Cut(msg.output, "\n")
+			m.statusMsg = "Committed " + summary
+			m.errorMsg = ""
+			cmds = append(cmds, loadGitDetail(msg.path, m.detailLogCount), checkGitStatus(msg.path))
+		} else {
+			m.statusMsg = ""
+			m.showError("Commit failed:\n\n" + msg.err)
+		}
+
+	case upstreamSetMsg:
+		if msg.success {
+			m.statusMsg = msg.branch + " now tracks " + msg.upstream
+			m.errorMsg = ""
+			cmds = append(cmds, loadBranches(msg.path), checkGitStatus(msg.path))
+		} else {
+			m.statusMsg = ""
+			m.errorMsg = "Set upstream failed: " + msg.err
+		}
+
+	case branchRenameMsg:
+		if msg.success {
+			m.statusMsg = "Renamed " + msg.oldName + " to " + msg.newName
+			m.errorMsg = ""
+			// Keep branch state in sync if the current branch was renamed
+			if m.detailRepo != nil && m.detailRepo.Path == msg.path && m.detailRepo.Branch == msg.oldName {
+				m.detailRepo.Branch = msg.newName
+			}
+			for i := range m.repos {
+				if m.repos[i].Path == msg.path && m.repos[i].Branch == msg.oldName {
+					m.repos[i].Branch = msg.newName
+					break
+				}
+			}
+			cmds = append(cmds, loadBranches(msg.path), loadGitDetail(msg.path, m.detailLogCount))
+		} else {
+			m.statusMsg = ""
+			m.errorMsg = "Rename failed: " + msg.err
+		}
+
+	case switchCheckedMsg:
+		if m.mode != detailView || m.detailRepo == nil || m.detailRepo.Path != msg.path || m.targetBranch != msg.branch {
+			break
+		}
+		if len(msg.clobbered) > 0 {
+			m.showError(fmt.Sprintf("Cannot switch to %s: it would overwrite %d untracked files.\nMove or delete them first:\n\n%s", msg.branch, len(msg.clobbered), strings.Join(msg.clobbered, "\n")))
+			break
+		}
+		if msg.tracked > 0 {
+			m.hasChanges = true
+			m.mode = actionSelectView
+			m.actionIndex = 0
+			break
+		}
+		if len(msg.untracked) > 0 {
+			// git carries untracked files across; make sure that's wanted
+			m.pendingSwitch = msg.ref
+			m.untrackedLeft = len(msg.untracked)
+			break
+		}
+		m.statusMsg = "Switching to " + msg.branch + "..."
+		cmds = append(cmds, switchBranch(msg.path, msg.ref))
+
 	case branchSwitchMsg:
 		if msg.success {
 			m.statusMsg = "Switched to " + msg.branch
+			if m.autoStashed {
+				m.statusMsg += " (changes auto-stashed, u: restore)"
+				m.autoStashed = false
+			}
 			m.errorMsg = ""
 			m.mode = detailView
 			if m.detailRepo != nil {
@@ -1244,18 +2737,54 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 			}
-			cmds = append(cmds, loadGitDetail(msg.path), loadBranches(msg.path), checkGitStatus(msg.path))
+			cmds = append(cmds, loadGitDetail(msg.path, m.detailLogCount), loadBranches(msg.path), checkGitStatus(msg.path))
 		} else {
-			m.errorMsg = "Branch switch failed:\n\n" + msg.err
-			m.previousMode = m.mode
-			if m.list.FilterState() == list.FilterApplied {
-				m.savedFilter = m.list.FilterValue()
+			m.showError("Branch switch failed:\n\n" + msg.err)
+		}
+
+	case commitLogLoadedMsg:
+		if m.detailRepo != nil && m.detailRepo.Path == msg.path && msg.query == m.logQuery && msg.skip == len(m.logCommits) {
+			m.logLoading = false
+			if msg.err != "" {
+				m.logErr = msg.err
+				m.logDone = true
+				break
+			}
+			m.logCommits = append(m.logCommits, msg.commits...)
+			m.logDone = len(msg.commits) < commitLogPageSize
+		}
+
+	case commitShowLoadedMsg:
+		if m.mode == commitShowView && m.detailRepo != nil && m.detailRepo.Path == msg.path && m.logShowHash == msg.hash {
+			m.viewport.SetContent(msg.content)
+		}
+
+	case opAbortedMsg:
+		if msg.err == "" {
+			m.statusMsg = "Aborted " + msg.op
+			m.errorMsg = ""
+		} else {
+			m.statusMsg = ""
+			m.errorMsg = msg.op + " --abort failed: " + msg.err
+		}
+		cmds = append(cmds, loadGitDetail(msg.path, m.detailLogCount), loadBranches(msg.path), checkGitStatus(msg.path))
+
+	case rebaseResultMsg:
+		if msg.err == nil {
+			m.statusMsg = "Rebased onto " + msg.onto
+			m.errorMsg = ""
+		} else {
+			m.statusMsg = ""
+			errMsg := "Rebase onto " + msg.onto + " failed:\n\n" + msg.output
+			if msg.conflicts > 0 {
+				errMsg += fmt.Sprintf("\n\n%d conflicts. Resolve them and run `git rebase --continue`, or press A in the detail view (or run `git rebase --abort` from the command pane) to go back.", msg.conflicts)
 			}
-			m.mode = errorView
-			m.viewport.SetContent(m.errorMsg)
+			m.showError(errMsg)
 		}
+		cmds = append(cmds, loadGitDetail(msg.path, m.detailLogCount), loadBranches(msg.path), checkGitStatus(msg.path))
 
 	case stashResultMsg:
+		m.autoStashed = msg.success
 		if msg.success {
 			if m.detailRepo != nil && m.targetBranch != "" {
 				m.mode = detailView
@@ -1264,37 +2793,161 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				cmds = append(cmds, switchBranch(m.detailRepo.Path, m.targetBranch))
 			}
 		} else {
-			m.errorMsg = "Operation failed:\n\n" + msg.err
-			m.previousMode = m.mode
-			if m.list.FilterState() == list.FilterApplied {
-				m.savedFilter = m.list.FilterValue()
+			m.showError("Operation failed:\n\n" + msg.err)
+		}
+
+	case filesLoadedMsg:
+		delete(m.filesLoading, msg.cacheKey)
+		// Cache failures as empty so they aren't retried on every expand
+		m.filesCache[msg.cacheKey] = msg.files
+
+	case autoStashPopMsg:
+		m.autoStashed = false
+		if msg.notFound {
+			m.statusMsg = "No guppi auto-stash to restore"
+		} else if msg.success {
+			m.statusMsg = "Restored auto-stash " + msg.ref
+			m.errorMsg = ""
+		} else {
+			// Usually conflicts with changes made since the stash; the stash is kept
+			m.statusMsg = ""
+			m.showError("Restoring auto-stash failed (the stash was kept):\n\n" + msg.err)
+		}
+		cmds = append(cmds, loadGitDetail(msg.path, m.detailLogCount), checkGitStatus(msg.path))
+
+	case stashesLoadedMsg:
+		if m.detailRepo != nil && m.detailRepo.Path == msg.path {
+			m.stashes = msg.stashes
+			if m.stashIndex >= len(m.stashes) {
+				m.stashIndex = 0
+			}
+		}
+
+	case stashActionMsg:
+		if msg.success {
+			m.statusMsg = fmt.Sprintf("Stash %s: %s", msg.action, msg.ref)
+			m.errorMsg = ""
+		} else {
+			m.statusMsg = ""
+			m.errorMsg = fmt.Sprintf("Stash %s failed: %s", msg.action, msg.err)
+		}
+		if m.detailRepo != nil {
+			cmds = append(cmds, loadStashes(m.detailRepo.Path), loadGitDetail(m.detailRepo.Path, m.detailLogCount), loadBranches(m.detailRepo.Path), checkGitStatus(m.detailRepo.Path))
+		}
+
+	case hookResultMsg:
+		repoName := filepath.Base(msg.path)
+		for i := range m.repos {
+			if m.repos[i].Path == msg.path {
+				repoName = m.repos[i].Name
+				break
+			}
+		}
+		if msg.err != nil {
+			m.statusMsg = ""
+			m.showError(fmt.Sprintf("Post-pull hook failed for %s:\n\n$ %s\n%s\n%s", repoName, msg.command, msg.err.Error(), msg.output))
+		} else {
+			summary := hookSummary(msg.output)
+			for i := range m.pullResults {
+				if m.pullResults[i].RepoPath == msg.path {
+					m.pullResults[i].Hook = summary
+				}
+			}
+			if !m.pulling {
+				m.statusMsg = "Post-pull hook done for " + repoName + ": " + summary
 			}
-			m.mode = errorView
-			m.viewport.SetContent(m.errorMsg)
 		}
 
-	case lazygitExitMsg:
-		m.statusMsg = "Back from lazygit"
+	case editorExitMsg:
+		if msg.err != nil {
+			m.statusMsg = "Editor failed: " + msg.err.Error()
+		} else {
+			m.statusMsg = "Back from editor"
+		}
+		if msg.path != "" {
+			cmds = append(cmds, checkGitStatus(msg.path))
+		}
+
+	case gitUIExitMsg:
+		m.statusMsg = "Back from " + toolName(m.gitUITool)
 		if msg.path != "" {
 			cmds = append(cmds, checkGitStatus(msg.path))
 		}
 		m.detailRepo = nil
 
-	case cmdResultMsg:
-		m.cmdRunning = false
+	case cloneProgressMsg:
+		m.statusMsg = msg.line
+		cmds = append(cmds, waitForCmdOutput(msg.stream))
+
+	case cloneCompleteMsg:
+		m.cloning = false
 		if msg.err != nil {
-			m.cmdOutput += statusErrorStyle.Render("Error: "+msg.err.Error()) + "\n\n"
+			m.statusMsg = ""
+			errMsg := fmt.Sprintf("Clone of %s failed: %v", msg.name, msg.err)
+			if msg.output != "" {
+				errMsg += "\n\n" + msg.output
+			}
+			m.showError(errMsg)
+		} else {
+			m.repos = append(m.repos, Repo{
+				Path:      msg.path,
+				Name:      msg.name,
+				Status:    StatusUnknown,
+				RemoteURL: originURL(msg.path),
+			})
+			m.updateList()
+			m.statusMsg = "Cloned " + msg.name
+			cmds = append(cmds, checkGitStatus(msg.path))
 		}
+
+	case cmdStartedMsg:
+		m.cmdProc = msg.proc
+		cmds = append(cmds, waitForCmdOutput(msg.stream))
+
+	case cmdOutputLineMsg:
+		m.cmdGotLines = true
+		m.cmdOutput += msg.line
+		m.cmdViewport.SetContent(m.cmdOutput)
+		m.cmdViewport.GotoBottom()
+		cmds = append(cmds, waitForCmdOutput(msg.stream))
+
+	case cmdResultMsg:
+		m.cmdRunning = false
+		m.cmdProc = nil
 		if msg.output != "" {
 			m.cmdOutput += msg.output
 		}
-		if msg.output == "" && msg.err == nil {
+		if m.cmdKilled {
+			m.cmdKilled = false
+			if !strings.HasSuffix(m.cmdOutput, "\n") {
+				m.cmdOutput += "\n"
+			}
+			m.cmdOutput += "\n" + statusDirtyStyle.Render("^C cancelled") + "\n"
+		} else if msg.err != nil {
+			if !strings.HasSuffix(m.cmdOutput, "\n") {
+				m.cmdOutput += "\n"
+			}
+			m.cmdOutput += "\n" + statusErrorStyle.Render("Error: "+msg.err.Error()) + "\n"
+		}
+		if msg.output == "" && msg.err == nil && !m.cmdGotLines {
 			m.cmdOutput += "(no output)\n"
 		}
+		if msg.err == nil && len(m.cmdQueue) > 0 && m.detailRepo != nil {
+			next := m.cmdQueue[0]
+			m.cmdQueue = m.cmdQueue[1:]
+			m.cmdRunning = true
+			m.cmdGotLines = false
+			m.cmdOutput += "\nRunning: " + next + "\n\n"
+			m.cmdViewport.SetContent(m.cmdOutput)
+			m.cmdViewport.GotoBottom()
+			cmds = append(cmds, runCommand(m.detailRepo.Path, next))
+			break
+		}
+		m.cmdQueue = nil
 		m.cmdViewport.SetContent(m.cmdOutput)
 		m.cmdViewport.GotoBottom()
 		if m.detailRepo != nil {
-			cmds = append(cmds, loadGitDetail(m.detailRepo.Path), loadBranches(m.detailRepo.Path), checkGitStatus(m.detailRepo.Path))
+			cmds = append(cmds, loadGitDetail(m.detailRepo.Path, m.detailLogCount), loadBranches(m.detailRepo.Path), checkGitStatus(m.detailRepo.Path))
 		}
 	}
 
@@ -1318,3 +2971,66 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	return m, tea.Batch(cmds...)
 }
+
+// handleMouse maps the wheel onto ↑/↓ so every view scrolls the way its keys
+// do, and clicks onto list rows and pull result rows
+func (m model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+	switch m.mode {
+	case configView, groupInputView, branchInputView, cloneInputView, commitInputView:
+		// Arrow keys edit or do nothing in text inputs
+		return m, nil
+	}
+	if m.list.FilterState() == list.Filtering {
+		return m, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	case tea.MouseButtonWheelDown:
+		return m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	case tea.MouseButtonLeft:
+	default:
+		return m, nil
+	}
+
+	switch m.mode {
+	case listView:
+		header := lipgloss.Height(m.list.Styles.TitleBar.Render(m.list.Styles.Title.Render(m.list.Title)))
+		if m.list.ShowStatusBar() {
+			header += lipgloss.Height(m.list.Styles.StatusBar.Render(" "))
+		}
+		row := msg.Y - header
+		if row < 0 {
+			return m, nil
+		}
+		rowHeight := m.delegate.Height() + m.delegate.Spacing()
+		if row%rowHeight >= m.delegate.Height() {
+			return m, nil // click on the gap between items
+		}
+		start, end := m.list.Paginator.GetSliceBounds(len(m.list.VisibleItems()))
+		if index := start + row/rowHeight; index < end {
+			m.list.Select(index)
+		}
+		return m, nil
+
+	case pullResultsView:
+		target, ok := pullResultsRowAt(m, msg.Y-lipgloss.Height(pullResultsHeader(m)))
+		if !ok {
+			return m, nil
+		}
+		cursor := m.pullResultsCursor
+		expanded := cursor.RepoIdx == target.RepoIdx && cursor.Level > target.Level &&
+			(target.Level == 0 || cursor.CommitIdx == target.CommitIdx)
+		m.pullResultsCursor = target
+		if expanded || target.Level == 2 {
+			// Clicking an expanded row collapses it, files just get selected
+			return m, nil
+		}
+		return m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	}
+	return m, nil
+}
@@ -7,7 +7,80 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Minimum terminal sizes below which layouts break down
+const (
+	minWidth        = 40
+	minHeight       = 10
+	minDetailWidth  = 80
+	minDetailHeight = 20
+)
+
+// renderTooSmall renders a notice asking the user to enlarge the terminal
+func renderTooSmall(width, height, needWidth, needHeight int) string {
+	msg := statusErrorStyle.Render("Terminal too small") + "\n" +
+		helpStyle.Render(fmt.Sprintf("%dx%d, need at least %dx%d", width, height, needWidth, needHeight))
+	return lipgloss.NewStyle().MaxWidth(width).Render(msg)
+}
+
+// batchProgressLabel renders a batch operation's progress, e.g. "Pulling 12/30..."
+func batchProgressLabel(verb string, done, total int) string {
+	return fmt.Sprintf("%s %d/%d...", verb, done, total)
+}
+
+// renderRepoSummary tallies repos by status, e.g. "42 repos · 5 dirty · 8 behind · 1 error".
+// Zero counts are left out to keep the line short.
+func renderRepoSummary(repos []Repo) string {
+	var clean, dirty, behind, conflicts, errors int
+	for _, r := range repos {
+		switch r.Status {
+		case StatusClean, StatusCleanBehind:
+			clean++
+		case StatusDirty:
+			dirty++
+		case StatusConflict:
+			conflicts++
+		case StatusError:
+			errors++
+		}
+		if r.BehindCount > 0 {
+			behind++
+		}
+	}
+
+	noun := "repos"
+	if len(repos) == 1 {
+		noun = "repo"
+	}
+	sep := helpStyle.Render(" · ")
+	parts := []string{helpStyle.Render(fmt.Sprintf("%d %s", len(repos), noun))}
+	if clean > 0 {
+		parts = append(parts, statusCleanStyle.Render(fmt.Sprintf("%d clean", clean)))
+	}
+	if dirty > 0 {
+		parts = append(parts, statusDirtyStyle.Render(fmt.Sprintf("%d dirty", dirty)))
+	}
+	if behind > 0 {
+		parts = append(parts, branchStyle.Render(fmt.Sprintf("%d behind", behind)))
+	}
+	if conflicts > 0 {
+		parts = append(parts, statusErrorStyle.Render(fmt.Sprintf("%d conflicted", conflicts)))
+	}
+	if errors > 0 {
+		noun := "errors"
+		if errors == 1 {
+			noun = "error"
+		}
+		parts = append(parts, statusErrorStyle.Render(fmt.Sprintf("%d %s", errors, noun)))
+	}
+	return strings.Join(parts, sep)
+}
+
 func (m model) View() string {
+	// Width/height are 0 until the first WindowSizeMsg arrives
+	if m.width > 0 && (m.width < minWidth || m.height < minHeight) {
+		return renderTooSmall(m.width, m.height, minWidth, minHeight)
+	}
+
 	if m.mode == configView {
 		title := detailTitleStyle.Render("Configure Git Directory")
 		help := helpStyle.Render("enter: save • esc: cancel")
@@ -20,6 +93,9 @@ func (m model) View() string {
 
 	if m.mode == groupInputView {
 		action := "Create New Group"
+		if m.currentGroup != nil {
+			action = "Create Subgroup in " + m.groupPath(m.currentGroup.Name)
+		}
 		if m.groupAction == "rename" {
 			action = "Rename Group"
 		}
@@ -29,6 +105,43 @@ func (m model) View() string {
 		return title + "\n\n" + input + "\n\n" + help
 	}
 
+	if m.mode == commitInputView && m.detailRepo != nil {
+		title := detailTitleStyle.Render("Commit staged changes in " + m.detailRepo.Name + " [" + m.detailRepo.Branch + "]")
+		help := helpStyle.Render("enter: commit • esc: cancel")
+		input := m.commitInput.View()
+		status := ""
+		if m.statusMsg != "" {
+			status = statusErrorStyle.Render(m.statusMsg) + "\n\n"
+		}
+		return title + "\n\n" + input + "\n\n" + status + help
+	}
+
+	if m.mode == cloneInputView {
+		title := detailTitleStyle.Render("Clone into " + m.gitDir)
+		help := helpStyle.Render("enter: clone • esc: cancel")
+		input := m.cloneInput.View()
+		status := ""
+		if m.statusMsg != "" {
+			status = statusErrorStyle.Render(m.statusMsg) + "\n\n"
+		}
+		return title + "\n\n" + input + "\n\n" + status + help
+	}
+
+	if m.mode == branchInputView && m.detailRepo != nil {
+		title := detailTitleStyle.Render("New branch in " + m.detailRepo.Name + " (from " + m.detailRepo.Branch + ")")
+		help := helpStyle.Render("enter: create & switch • esc: cancel")
+		if m.branchAction == "rename" {
+			title = detailTitleStyle.Render("Rename branch " + m.renameFrom + " in " + m.detailRepo.Name)
+			help = helpStyle.Render("enter: rename • esc: cancel")
+		}
+		input := m.branchInput.View()
+		status := ""
+		if m.statusMsg != "" {
+			status = statusErrorStyle.Render(m.statusMsg) + "\n\n"
+		}
+		return title + "\n\n" + input + "\n\n" + status + help
+	}
+
 	if m.mode == groupDeleteView && m.currentGroup != nil {
 		title := statusErrorStyle.Render("Delete Group: " + m.currentGroup.Name + "?")
 		subtitle := helpStyle.Render(fmt.Sprintf("This group contains %d repos. They will be ungrouped.", len(m.currentGroup.Repos)))
@@ -36,8 +149,12 @@ func (m model) View() string {
 		return title + "\n\n" + subtitle + "\n\n" + help
 	}
 
-	if m.mode == groupSelectView && m.selectedRepo != nil {
-		title := detailTitleStyle.Render("Move " + m.selectedRepo.Name + " to group:")
+	if m.mode == groupSelectView && len(m.moveRepos) > 0 {
+		what := m.moveRepos[0].Name
+		if len(m.moveRepos) > 1 {
+			what = fmt.Sprintf("%d repos", len(m.moveRepos))
+		}
+		title := detailTitleStyle.Render("Move " + what + " to group:")
 
 		var list strings.Builder
 		for i, g := range m.groups {
@@ -45,26 +162,27 @@ func (m model) View() string {
 			style := lipgloss.NewStyle()
 			if i == m.groupIndex {
 				prefix = "> "
-				style = style.Bold(true).Foreground(lipgloss.Color("205"))
+				style = selectedStyle.Inherit(style)
 			}
-			inGroup := false
+			members := make(map[string]bool)
 			for _, p := range g.Repos {
-				if p == m.selectedRepo.Path {
-					inGroup = true
-					break
-				}
+				members[p] = true
+			}
+			inGroup := true
+			for _, r := range m.moveRepos {
+				inGroup = inGroup && members[r.Path]
 			}
 			indicator := ""
 			if inGroup {
 				indicator = " ✓"
 			}
-			list.WriteString(prefix + style.Render("📁 "+g.Name+indicator) + "\n")
+			list.WriteString(prefix + style.Render("📁 "+m.groupPath(g.Name)+indicator) + "\n")
 		}
 		prefix := "  "
-		style := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+		style := statusDirtyStyle
 		if m.groupIndex == len(m.groups) {
 			prefix = "> "
-			style = style.Bold(true).Foreground(lipgloss.Color("205"))
+			style = selectedStyle.Inherit(style)
 		}
 		list.WriteString(prefix + style.Render("(Remove from group)") + "\n")
 
@@ -87,7 +205,7 @@ func (m model) View() string {
 			style := lipgloss.NewStyle()
 			if i == m.addRepoIndex {
 				prefix = "> "
-				style = style.Bold(true).Foreground(lipgloss.Color("205"))
+				style = selectedStyle.Inherit(style)
 			}
 			list.WriteString(prefix + style.Render(repo.Name) + "\n")
 		}
@@ -103,23 +221,58 @@ func (m model) View() string {
 		return title + "\n\n" + list.String() + "\n" + status + help
 	}
 
+	if g, ok := m.groupsMap[m.settingsGroup]; ok && m.mode == groupSettingsView {
+		title := detailTitleStyle.Render("Group settings: " + m.groupPath(g.Name))
+
+		rows := []struct {
+			name  string
+			value string
+			desc  string
+		}{
+			{"Pull strategy", m.pullStrategyLabel(g.PullStrategy), "How p, P, A and U pull repos in this group and its subgroups"},
+			{"Fetch mode", m.fetchModeLabel(g.FetchMode), "Which repos r refreshes inside this group"},
+		}
+		var options strings.Builder
+		for i, row := range rows {
+			prefix := "  "
+			style := lipgloss.NewStyle()
+			if i == m.groupSetIndex {
+				prefix = "> "
+				style = selectedStyle.Inherit(style)
+			}
+			options.WriteString(prefix + style.Render(row.name+": "+row.value) + "\n")
+			options.WriteString("     " + helpStyle.Render(row.desc) + "\n\n")
+		}
+
+		status := ""
+		if m.statusMsg != "" {
+			status = successStyle.Render(m.statusMsg) + "\n"
+		}
+		help := helpStyle.Render("↑/↓: select • ←/→/enter: change • esc: back")
+		return title + "\n\n" + options.String() + status + help
+	}
+
 	if m.mode == detailView && m.detailRepo != nil {
+		if m.width > 0 && (m.width < minDetailWidth || m.height < minDetailHeight) {
+			return renderTooSmall(m.width, m.height, minDetailWidth, minDetailHeight)
+		}
+
 		title := detailTitleStyle.Render(fmt.Sprintf(" %s [%s]", m.detailRepo.Name, m.detailRepo.Branch))
 
 		totalWidth := m.width
 		if totalWidth < 80 {
 			totalWidth = 80
 		}
-		leftWidth := (totalWidth * 60) / 100
-		rightWidth := (totalWidth * 40) / 100
+		leftWidth := (totalWidth * m.detailStatusPct) / 100
+		rightWidth := (totalWidth * (100 - m.detailStatusPct)) / 100
 
 		focusedBorder := lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("205")).
+			BorderForeground(selectedStyle.GetForeground()).
 			Padding(0, 1)
 		normalBorder := lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("241")).
+			BorderForeground(helpStyle.GetForeground()).
 			Padding(0, 1)
 
 		statusTitle := "Status"
@@ -131,16 +284,16 @@ func (m model) View() string {
 			statusStyle = focusedBorder.Width(leftWidth - 4)
 		}
 
-		statusHeight := (m.height - 12) / 2
-		if statusHeight < 5 {
-			statusHeight = 5
-		}
+		statusHeight := m.detailPaneHeight()
 		m.viewport.Width = leftWidth - 6
 		m.viewport.Height = statusHeight
 		statusContent := m.viewport.View()
 		statusPane := statusStyle.Height(statusHeight + 2).Render(branchStyle.Render(statusTitle) + "\n" + statusContent)
 
 		branchTitle := "Branches"
+		if m.defaultBranch != "" {
+			branchTitle += " (default: " + m.defaultBranch + ")"
+		}
 		if m.detailFocus == paneBranches {
 			branchTitle = "● " + branchTitle
 		}
@@ -150,10 +303,16 @@ func (m model) View() string {
 		}
 
 		var branchList strings.Builder
-		if len(m.branches) == 0 {
+		maxBranches := statusHeight
+		if m.filteringBr || m.branchFilter.Value() != "" {
+			branchList.WriteString(m.branchFilter.View() + "\n")
+			maxBranches--
+		}
+		if len(m.allBranches) == 0 {
 			branchList.WriteString("Loading...")
+		} else if len(m.branches) == 0 {
+			branchList.WriteString(helpStyle.Render("No matching branches"))
 		} else {
-			maxBranches := statusHeight
 			startIdx := 0
 			if m.branchIndex >= maxBranches {
 				startIdx = m.branchIndex - maxBranches + 1
@@ -169,30 +328,42 @@ func (m model) View() string {
 					indicator = " ↕"
 				} else if branch.IsLocal && !branch.IsRemote {
 					indicator = " ⚠"
-					style = style.Foreground(lipgloss.Color("214"))
+					style = style.Foreground(statusDirtyStyle.GetForeground())
 				} else if !branch.IsLocal && branch.IsRemote {
 					indicator = " ☁"
-					style = style.Foreground(lipgloss.Color("39"))
+					style = style.Foreground(branchStyle.GetForeground())
 				}
 
 				if i == m.branchIndex {
 					prefix = "> "
-					style = style.Bold(true).Foreground(lipgloss.Color("205"))
+					style = selectedStyle.Inherit(style)
 				}
 				if branch.IsCurrent {
 					displayName = branch.Name + " ✓"
 					if i != m.branchIndex {
-						style = style.Foreground(lipgloss.Color("42"))
+						style = style.Foreground(statusCleanStyle.GetForeground())
 					}
 					indicator = ""
 				}
+				if branch.IsDefault {
+					indicator += " ◆"
+				}
+				if branch.NoUpstream {
+					indicator += " ⊘ no upstream"
+				}
+				if branch.Ahead > 0 {
+					indicator += fmt.Sprintf(" ↑%d", branch.Ahead)
+				}
+				if branch.Behind > 0 {
+					indicator += fmt.Sprintf(" ↓%d", branch.Behind)
+				}
 				branchList.WriteString(prefix + style.Render(displayName+indicator) + "\n")
 			}
 			if len(m.branches) > maxBranches {
 				branchList.WriteString(helpStyle.Render(fmt.Sprintf("  ... %d more", len(m.branches)-maxBranches)))
 			}
 		}
-		branchPane := branchPaneStyle.Height(statusHeight + 2).Render(lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Render(branchTitle) + "\n" + branchList.String())
+		branchPane := branchPaneStyle.Height(statusHeight + 2).Render(branchStyle.Render(branchTitle) + "\n" + branchList.String())
 
 		topRow := lipgloss.JoinHorizontal(lipgloss.Top, statusPane, branchPane)
 
@@ -205,7 +376,7 @@ func (m model) View() string {
 			cmdStyle = focusedBorder.Width(totalWidth - 4)
 		}
 
-		cmdHeight := 6
+		cmdHeight := m.detailCmdHeight
 		m.cmdViewport.Width = totalWidth - 8
 		m.cmdViewport.Height = cmdHeight - 2
 
@@ -216,17 +387,33 @@ func (m model) View() string {
 		} else {
 			cmdContent += helpStyle.Render("Output will appear here...")
 		}
-		cmdPane := cmdStyle.Render(lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Render(cmdTitle) + "\n" + cmdContent)
+		cmdPane := cmdStyle.Render(branchStyle.Render(cmdTitle) + "\n" + cmdContent)
 
 		var statusLine string
-		if m.errorMsg != "" {
+		if m.remoteDelete != "" {
+			statusLine = statusErrorStyle.Render("Delete " + m.remoteDelete + " on the remote? This cannot be undone. (y/n)")
+		} else if m.forceDelete != "" {
+			statusLine = statusErrorStyle.Render(fmt.Sprintf("Force delete %s? %d commits not merged into HEAD will be lost. (y/n)", m.forceDelete, m.forceLoss))
+		} else if m.resetConfirm == 1 {
+			statusLine = statusErrorStyle.Render("Reset --hard to " + m.resetUpstream + "? Discards " + m.resetLoss + ". (y/n)")
+		} else if m.resetConfirm == 2 {
+			statusLine = statusErrorStyle.Render("Really discard " + m.resetLoss + "? This cannot be undone. (y/n)")
+		} else if m.abortOp != "" {
+			statusLine = statusErrorStyle.Render("Abort the " + m.abortOp + "? Conflict resolutions so far are lost. (y/n)")
+		} else if m.rebaseOnto != "" && m.detailRepo != nil {
+			statusLine = statusDirtyStyle.Render("Rebase " + m.detailRepo.Branch + " onto " + m.rebaseOnto + "? (y/n)")
+		} else if m.pendingSwitch != "" {
+			statusLine = statusDirtyStyle.Render(fmt.Sprintf("Switch to %s? %d untracked files stay in the working tree. (y/n)", m.targetBranch, m.untrackedLeft))
+		} else if m.gcConfirm {
+			statusLine = statusDirtyStyle.Render("Run git gc? Can be slow on big repos. (y: gc • p: gc + remote prune origin • n: cancel)")
+		} else if m.errorMsg != "" {
 			statusLine = statusErrorStyle.Render("Error: " + m.errorMsg)
 		} else if m.statusMsg != "" {
 			statusLine = successStyle.Render(m.statusMsg)
 		}
 
-		help := helpStyle.Render("tab: pane • ↑/↓: scroll • enter: switch/run • p: pull remote • x: delete local • r: refresh • esc: back")
-		help2 := helpStyle.Render("↕ local+remote • ⚠ local only • ☁ remote only")
+		help := helpStyle.Render("tab: pane • ↑/↓: scroll • enter: diff/switch/run • /: filter • n: new branch • e: rename • p: pull remote • t: track origin • b: rebase onto • x/D: delete local/remote • space: stage • c: commit • r: refresh • s: stashes • u: unstash • H: reset • R: reflog • L: log • z: size • M: gc • A: abort merge/rebase • ?: help • esc: back")
+		help2 := helpStyle.Render("↕ local+remote • ⚠ local only • ☁ remote only • ◆ default • ctrl+g: git prefix (command pane)")
 
 		return title + "\n" + topRow + "\n" + cmdPane + "\n" + statusLine + "\n" + help + "\n" + help2
 	}
@@ -242,10 +429,10 @@ func (m model) View() string {
 			style := lipgloss.NewStyle()
 			if i == m.actionIndex {
 				prefix = "> "
-				style = style.Bold(true).Foreground(lipgloss.Color("205"))
+				style = selectedStyle.Inherit(style)
 			}
 			if i == 1 {
-				style = style.Foreground(lipgloss.Color("196"))
+				style = style.Foreground(statusErrorStyle.GetForeground())
 			}
 			actionList.WriteString(prefix + style.Render(action) + "\n")
 		}
@@ -254,11 +441,126 @@ func (m model) View() string {
 		return title + "\n\n" + subtitle + "\n\n" + actionList.String() + "\n" + help
 	}
 
+	if m.mode == stashView && m.detailRepo != nil {
+		title := detailTitleStyle.Render("Stashes: " + m.detailRepo.Name)
+
+		var stashList strings.Builder
+		if len(m.stashes) == 0 {
+			stashList.WriteString(helpStyle.Render("  No stashes"))
+			stashList.WriteString("\n")
+		}
+		for i, s := range m.stashes {
+			prefix := "  "
+			style := lipgloss.NewStyle()
+			if i == m.stashIndex {
+				prefix = "> "
+				style = selectedStyle.Inherit(style)
+			}
+			stashList.WriteString(prefix + style.Render(s.Ref+": "+s.Message) + " " + helpStyle.Render("("+s.Time+")") + "\n")
+		}
+
+		var statusLine string
+		if m.stashConfirm != "" && m.stashIndex < len(m.stashes) {
+			statusLine = statusDirtyStyle.Render(fmt.Sprintf("%s %s? (y/n)", strings.ToUpper(m.stashConfirm[:1])+m.stashConfirm[1:], m.stashes[m.stashIndex].Ref))
+		} else if m.errorMsg != "" {
+			statusLine = statusErrorStyle.Render("Error: " + m.errorMsg)
+		} else if m.statusMsg != "" {
+			statusLine = successStyle.Render(m.statusMsg)
+		}
+
+		help := helpStyle.Render("↑/↓: select • a/enter: apply • p: pop • d: drop • esc: back")
+		return title + "\n\n" + stashList.String() + "\n" + statusLine + "\n" + help
+	}
+
+	if m.mode == diffView && m.detailRepo != nil {
+		title := detailTitleStyle.Render(fmt.Sprintf("Diff: %s — %s", m.detailRepo.Name, m.diffFile))
+		help := helpStyle.Render("↑/↓: scroll • esc: back")
+		content := m.viewport.View()
+		return title + "\n\n" + content + "\n\n" + help
+	}
+
+	if m.mode == commitLogView && m.detailRepo != nil {
+		title := detailTitleStyle.Render(fmt.Sprintf("Commits: %s [%s]", m.detailRepo.Name, m.detailRepo.Branch))
+
+		var sb strings.Builder
+		if m.logSearching {
+			sb.WriteString(m.logSearch.View() + "\n")
+		} else if m.logQuery != "" {
+			sb.WriteString(helpStyle.Render("Messages containing \""+m.logQuery+"\" (esc: clear)") + "\n")
+		}
+
+		height := m.commitLogHeight()
+		start := 0
+		if m.logIndex >= height {
+			start = m.logIndex - height + 1
+		}
+		for i := start; i < len(m.logCommits) && i < start+height; i++ {
+			c := m.logCommits[i]
+			prefix := "  "
+			style := lipgloss.NewStyle()
+			if i == m.logIndex {
+				prefix = "> "
+				style = selectedStyle.Inherit(style)
+			}
+			sb.WriteString(prefix + prCommitHash.Render(c.Hash) + " " + style.Render(truncateRunes(c.Message, 72)) + " " + helpStyle.Render(c.Author+", "+c.Time) + "\n")
+		}
+
+		var statusLine string
+		switch {
+		case m.logErr != "":
+			statusLine = statusErrorStyle.Render("Error: " + m.logErr)
+		case m.logLoading:
+			statusLine = helpStyle.Render("Loading commits...")
+		case len(m.logCommits) == 0 && m.logQuery != "":
+			statusLine = helpStyle.Render("No commits match")
+		case len(m.logCommits) == 0:
+			statusLine = helpStyle.Render("No commits")
+		case m.logDone:
+			statusLine = helpStyle.Render(fmt.Sprintf("%d/%d", m.logIndex+1, len(m.logCommits)))
+		default:
+			statusLine = helpStyle.Render(fmt.Sprintf("%d/%d+ (more load on scroll)", m.logIndex+1, len(m.logCommits)))
+		}
+
+		help := helpStyle.Render("↑/↓: select • pgup/pgdn: page • enter: show commit • /: search messages • esc: back")
+		return title + "\n\n" + sb.String() + "\n" + statusLine + "\n" + help
+	}
+
+	if m.mode == commitShowView && m.detailRepo != nil {
+		title := detailTitleStyle.Render(fmt.Sprintf("Commit %s: %s", m.logShowHash, m.detailRepo.Name))
+		help := helpStyle.Render("↑/↓: scroll • esc: back to log")
+		content := m.viewport.View()
+		return title + "\n\n" + content + "\n\n" + help
+	}
+
+	if m.mode == reflogView && m.detailRepo != nil {
+		title := detailTitleStyle.Render(fmt.Sprintf("Reflog: %s [%s]", m.detailRepo.Name, m.detailRepo.Branch))
+		help := helpStyle.Render("↑/↓: scroll • esc: back")
+		content := m.viewport.View()
+		return title + "\n\n" + content + "\n\n" + help
+	}
+
+	if m.mode == helpView {
+		title := detailTitleStyle.Render("Key Bindings")
+		help := helpStyle.Render("↑/↓: scroll • ?/esc: close")
+		content := m.viewport.View()
+		return title + "\n\n" + content + "\n\n" + help
+	}
+
 	if m.mode == errorView {
 		title := statusErrorStyle.Render("Error")
-		help := helpStyle.Render("↑/↓: scroll • esc/enter: dismiss")
+		keys := "↑/↓: scroll • esc/enter: dismiss"
+		if len(m.retryPaths) == 1 {
+			keys = "r: retry pull • " + keys
+		} else if len(m.retryPaths) > 1 {
+			keys = fmt.Sprintf("r: retry %d pulls • %s", len(m.retryPaths), keys)
+		}
+		help := helpStyle.Render(keys)
 		content := m.viewport.View()
-		return title + "\n\n" + content + "\n\n" + help
+		status := ""
+		if m.pulling {
+			status = m.spinner.View() + " " + m.statusMsg + "\n\n"
+		}
+		return title + "\n\n" + content + "\n\n" + status + help
 	}
 
 	if m.mode == settingsView {
@@ -282,7 +584,7 @@ func (m model) View() string {
 			style := lipgloss.NewStyle()
 			if i == m.settingsIndex {
 				prefix = "> "
-				style = style.Bold(true).Foreground(lipgloss.Color("205"))
+				style = selectedStyle.Inherit(style)
 			}
 
 			radio := "( )"
@@ -302,7 +604,7 @@ func (m model) View() string {
 		style := lipgloss.NewStyle()
 		if m.settingsIndex == 3 {
 			prefix = "> "
-			style = style.Bold(true).Foreground(lipgloss.Color("205"))
+			style = selectedStyle.Inherit(style)
 		}
 		toggle := "[ ]"
 		if m.showPullResults {
@@ -316,11 +618,90 @@ func (m model) View() string {
 		style = lipgloss.NewStyle()
 		if m.settingsIndex == 4 {
 			prefix = "> "
-			style = style.Bold(true).Foreground(lipgloss.Color("205"))
+			style = selectedStyle.Inherit(style)
 		}
 		optionsList.WriteString(prefix + style.Render(fmt.Sprintf("Max commits per repo: %d", m.maxCommitsPerRepo)) + "\n")
 		optionsList.WriteString("     " + helpStyle.Render("←/→ to adjust, max commits shown in pull results") + "\n\n")
 
+		// Refresh section
+		optionsList.WriteString(branchStyle.Render("Refresh") + "\n\n")
+
+		// Auto-fetch on refresh toggle (index 5)
+		prefix = "  "
+		style = lipgloss.NewStyle()
+		if m.settingsIndex == 5 {
+			prefix = "> "
+			style = selectedStyle.Inherit(style)
+		}
+		toggle = "[ ]"
+		if m.autoFetch {
+			toggle = "[✓]"
+		}
+		optionsList.WriteString(prefix + style.Render(toggle+" Auto-fetch on refresh") + "\n")
+		optionsList.WriteString("     " + helpStyle.Render("Fetch from remote before checking status; disable for fast offline refresh") + "\n\n")
+
+		// Auto-refresh interval (index 6)
+		prefix = "  "
+		style = lipgloss.NewStyle()
+		if m.settingsIndex == 6 {
+			prefix = "> "
+			style = selectedStyle.Inherit(style)
+		}
+		interval := "off"
+		if m.autoRefresh > 0 {
+			interval = fmt.Sprintf("every %ds", m.autoRefresh)
+		}
+		optionsList.WriteString(prefix + style.Render("Auto-refresh: "+interval) + "\n")
+		optionsList.WriteString("     " + helpStyle.Render("←/→ to adjust, re-checks visible repos in the background") + "\n\n")
+
+		// Batch Pull section
+		optionsList.WriteString(branchStyle.Render("Batch Pull") + "\n\n")
+
+		// Skip dirty repos toggle (index 7)
+		prefix = "  "
+		style = lipgloss.NewStyle()
+		if m.settingsIndex == 7 {
+			prefix = "> "
+			style = selectedStyle.Inherit(style)
+		}
+		toggle = "[ ]"
+		if m.skipDirty {
+			toggle = "[✓]"
+		}
+		optionsList.WriteString(prefix + style.Render(toggle+" Skip dirty repos in batch pulls") + "\n")
+		optionsList.WriteString("     " + helpStyle.Render("Leave repos with local changes alone for P, A and U") + "\n\n")
+
+		// Autostash toggle (index 8)
+		prefix = "  "
+		style = lipgloss.NewStyle()
+		if m.settingsIndex == 8 {
+			prefix = "> "
+			style = selectedStyle.Inherit(style)
+		}
+		toggle = "[ ]"
+		if m.pullAutostash {
+			toggle = "[✓]"
+		}
+		optionsList.WriteString(prefix + style.Render(toggle+" Pull with --rebase --autostash") + "\n")
+		optionsList.WriteString("     " + helpStyle.Render("Stash local changes, rebase onto upstream, reapply (dirty repos are pulled too)") + "\n\n")
+
+		// Display section
+		optionsList.WriteString(branchStyle.Render("Display") + "\n\n")
+
+		// Color by activity toggle (index 9)
+		prefix = "  "
+		style = lipgloss.NewStyle()
+		if m.settingsIndex == 9 {
+			prefix = "> "
+			style = selectedStyle.Inherit(style)
+		}
+		toggle = "[ ]"
+		if m.delegate.colorByActivity {
+			toggle = "[✓]"
+		}
+		optionsList.WriteString(prefix + style.Render(toggle+" Color repos by activity") + "\n")
+		optionsList.WriteString("     " + helpStyle.Render("Green when HEAD was committed within a day, dim after a month") + "\n\n")
+
 		help := helpStyle.Render("↑/↓: select • enter/space: toggle • ←/→: adjust • esc: back")
 		return title + "\n" + optionsList.String() + help
 	}
@@ -329,9 +710,22 @@ func (m model) View() string {
 		return renderPullResultsView(m)
 	}
 
+	if m.mode == pullPreviewView {
+		title := detailTitleStyle.Render("Pull Preview (" + m.previewScope + ")")
+		ready := 0
+		for _, entry := range m.pullPreview {
+			if entry.Reason() == "" && !m.previewSkipsDirty(entry) {
+				ready++
+			}
+		}
+		summary := successStyle.Render(fmt.Sprintf("%d of %d repos will be pulled", ready, len(m.pullPreview)))
+		help := helpStyle.Render("enter/p: pull them • ↑/↓: scroll • esc: cancel")
+		return title + "\n" + summary + "\n\n" + m.viewport.View() + "\n\n" + help
+	}
+
 	// Build filter indicator
 	var filterIndicator string
-	if m.filterDirty || m.filterBehind {
+	if m.filterDirty || m.filterBehind || m.filterDetached || m.filterConflict || m.hideClean {
 		var filters []string
 		if m.filterDirty {
 			filters = append(filters, "local changes")
@@ -339,40 +733,143 @@ func (m model) View() string {
 		if m.filterBehind {
 			filters = append(filters, "behind remote")
 		}
+		if m.filterDetached {
+			filters = append(filters, "detached HEAD")
+		}
+		if m.filterConflict {
+			filters = append(filters, "conflicts")
+		}
+		if m.hideClean {
+			filters = append(filters, "not clean")
+		}
 		filterIndicator = statusDirtyStyle.Render("[Filter: " + strings.Join(filters, " + ") + "] ")
 	}
+	if m.sortMode != SortByName {
+		filterIndicator += branchStyle.Render("[Sort: " + m.sortMode.String() + "] ")
+	}
 
 	var status string
-	if m.scanning {
+	if m.quitKey != "" {
+		status = statusDirtyStyle.Render("Operations in progress, press " + m.quitKey + " again to quit")
+	} else if m.scanning {
 		status = m.spinner.View() + " Scanning for repositories..."
+	} else if m.pulling && m.batchOp == "pull" && m.progressTotal > 0 {
+		// Show completed/total and progress bar for batch pulls, plus the latest result
+		status = m.spinner.View() + " " + batchProgressLabel("Pulling", m.progressDone, m.progressTotal) + " " + m.progress.View()
+		if m.progressDone > 0 && m.statusMsg != "" {
+			status += "  " + helpStyle.Render(m.statusMsg)
+		}
 	} else if m.pulling {
-		// Show progress bar for pull operations
+		// Single pull
 		status = m.spinner.View() + " " + m.statusMsg + " " + m.progress.View()
 	} else if m.batchOp == "fetch" && m.progressTotal > 0 {
-		// Show progress bar for fetch operations
-		status = m.statusMsg + " " + m.progress.View()
+		// Show completed/total and progress bar for batch status refreshes
+		verb := "Refreshing"
+		if m.fetchingOnly() {
+			verb = "Fetching"
+		}
+		status = m.spinner.View() + " " + batchProgressLabel(verb, m.progressDone, m.progressTotal) + " " + m.progress.View()
 	} else if m.errorMsg != "" {
 		status = statusErrorStyle.Render(m.errorMsg)
+	} else if repo, ok := m.list.SelectedItem().(Repo); ok && repo.Status == StatusError && repo.StatusErr != "" {
+		// Show why the selected repo failed; the row only says "failed to get status"
+		firstLine, _, _ := strings.Cut(repo.StatusErr, "\n")
+		status = statusErrorStyle.Render("✗ " + firstLine)
+		if isDubiousOwnership(repo.StatusErr) {
+			status = statusErrorStyle.Render("✗ git refuses this repo: owned by another user") + helpStyle.Render(m.keys.hint(" ({trust}: trust via safe.directory)"))
+		}
 	} else if m.statusMsg != "" {
 		status = filterIndicator + successStyle.Render(m.statusMsg)
 	} else if filterIndicator != "" {
 		status = filterIndicator
 	}
 
+	// Prefix a status tally of all repos unless a progress bar needs the room
+	if !m.scanning && !m.pulling && m.batchOp != "fetch" && len(m.repos) > 0 {
+		summary := renderRepoSummary(m.repos)
+		if status != "" {
+			status = summary + "  " + status
+		} else {
+			status = summary
+		}
+	}
+
 	var help, help2 string
 	if m.currentGroup != nil {
 		// Inside a group - always showing repos
-		help = helpStyle.Render("s: lazygit • d: details • o: open web • f: fav • p: pull • P: pull all • g: goto • r: refresh • x: remove")
-		help2 = helpStyle.Render("a: add repos • 1: dirty • 2: behind • 0: clear • /: search • m: move • esc: back • q: quit")
+		help = helpStyle.Render(m.keys.hint("{gitUI}: " + toolName(m.gitUITool) + " • {details}: details • {editor}: editor • {web}/{branchWeb}: web/branch • {favorite}: fav • {pull}: pull • {pullFavorites}: pull all • {goto}: goto • {refresh}: refresh • {remove}: remove"))
+		help2 = helpStyle.Render(m.keys.hint("{addRepos}: add repos • {groupSettings}: group settings • {filterDirty}: dirty • {filterBehind}: behind • {clearFilters}: clear • {sort}: sort • /: search • space: select • {moveRepo}: move • {back}: back • {quit}: quit"))
 	} else if _, isGroup := m.list.SelectedItem().(GroupItem); isGroup {
 		// Homepage with a group selected
-		help = helpStyle.Render("enter: open group • P: pull group • r: refresh group • e: rename • x: delete group • n: new group • /: search")
-		help2 = helpStyle.Render("A: pull behind • ctrl+r: refresh all • c: config • S: settings • q: quit")
+		help = helpStyle.Render(m.keys.hint("enter: open group • {expandGroup}: expand • {pullFavorites}: pull group • {refresh}: refresh group • {renameGroup}: rename • {groupSettings}: settings • K/J: move • {remove}: delete group • {newGroup}: new group • /: search"))
+		help2 = helpStyle.Render(m.keys.hint("{pullBehind}: pull behind • {pullAll}: pull all • {pullPreview}: preview • {fullRefresh}: refresh all • {configure}: config • {settings}: settings • {help}: help • {quit}: quit"))
 	} else {
 		// Homepage with a repo selected
-		help = helpStyle.Render("s: lazygit • d: details • o: open web • f: fav • p: pull • P: pull favs • g: goto • r/ctrl+r: refresh")
-		help2 = helpStyle.Render("A: pull behind • n: new group • m: move repo • /: search • c: config • S: settings • q: quit")
+		help = helpStyle.Render(m.keys.hint("{gitUI}: " + toolName(m.gitUITool) + " • {details}: details • {editor}: editor • {web}/{branchWeb}: web/branch • {favorite}: fav • {pull}: pull • {pullFavorites}: pull favs • {goto}: goto • {refresh}/{fullRefresh}: refresh"))
+		help2 = helpStyle.Render(m.keys.hint("{pullBehind}: pull behind • {pullAll}: pull all • {clone}: clone • {newGroup}: new group • space/{moveRepo}: select/move • {sort}: sort • /: search • {configure}: config • {settings}: settings • {help}: help • {quit}: quit"))
+	}
+
+	// Degrade gracefully on small terminals: clip lines and drop the second help line
+	if m.width > 0 {
+		clip := lipgloss.NewStyle().MaxWidth(m.width)
+		status = clip.Render(status)
+		help = clip.Render(help)
+		help2 = clip.Render(help2)
+	}
+	if m.height > 0 && m.height < 20 {
+		return m.list.View() + "\n" + status + "\n" + help
 	}
 
 	return m.list.View() + "\n" + status + "\n" + help + "\n" + help2
 }
+
+// renderPullPreview lists each previewed repo with what pulling would do;
+// skipsDirty reports the dirty repos the pull will leave out
+func renderPullPreview(entries []PullPreviewEntry, skipsDirty func(PullPreviewEntry) bool) string {
+	nameWidth := 0
+	for _, entry := range entries {
+		if w := lipgloss.Width(entry.RepoName); w > nameWidth {
+			nameWidth = w
+		}
+	}
+
+	var sb strings.Builder
+	for _, entry := range entries {
+		name := entry.RepoName + strings.Repeat(" ", nameWidth-lipgloss.Width(entry.RepoName))
+		var outcome string
+		switch reason := entry.Reason(); {
+		case reason == "up to date":
+			outcome = helpStyle.Render(reason)
+		case reason == "diverged from upstream":
+			outcome = statusErrorStyle.Render(fmt.Sprintf("↑%d ↓%d diverged, needs merge or rebase", entry.Ahead, entry.Behind))
+		case reason != "":
+			outcome = statusDirtyStyle.Render(reason)
+		case skipsDirty(entry):
+			outcome = statusDirtyStyle.Render(fmt.Sprintf("↓%d, skipped: uncommitted changes", entry.Behind))
+		case entry.Strategy == PullRebase:
+			outcome = statusCleanStyle.Render(fmt.Sprintf("↓%d rebase", entry.Behind))
+			if entry.Ahead > 0 {
+				outcome = statusCleanStyle.Render(fmt.Sprintf("↑%d ↓%d rebase", entry.Ahead, entry.Behind))
+			}
+			if entry.Dirty {
+				outcome += " " + statusDirtyStyle.Render("(uncommitted changes, autostashed)")
+			}
+		default:
+			outcome = statusCleanStyle.Render(fmt.Sprintf("↓%d fast-forward", entry.Behind))
+			if entry.Dirty {
+				outcome += " " + statusDirtyStyle.Render("(uncommitted changes)")
+			}
+		}
+		sb.WriteString("  " + name + "  " + outcome + "\n")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// wrapText soft-wraps s to width so long lines (conflict paths, git hints)
+// stay visible in a viewport instead of being cut off at the edge
+func wrapText(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	return lipgloss.NewStyle().Width(width).Render(s)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestWrapTextKeepsEveryLineWithinWidth(t *testing.T) {
+	long := "CONFLICT (content): Merge conflict in src/components/very/deeply/nested/directory/structure/Component.tsx"
+	wrapped := wrapText(long+"\n"+strings.Repeat("x", 50), 20)
+	for _, line := range strings.Split(wrapped, "\n") {
+		if w := lipgloss.Width(line); w > 20 {
+			t.Errorf("line %q is %d wide, want <= 20", line, w)
+		}
+	}
+	if got := strings.Join(strings.Fields(wrapped), ""); !strings.Contains(got, "Component.tsx") {
+		t.Errorf("wrapped text lost content: %q", wrapped)
+	}
+}
+
+func TestRenderPullPreviewSkipsOnlyDirtyReposThePullSkips(t *testing.T) {
+	entries := []PullPreviewEntry{
+		{RepoPath: "/g/api", RepoName: "api", HasUpstream: true, Behind: 2, Dirty: true},
+		{RepoPath: "/g/web", RepoName: "web", HasUpstream: true, Behind: 1, Dirty: true},
+	}
+	out := renderPullPreview(entries, func(e PullPreviewEntry) bool { return e.RepoPath == "/g/api" })
+	if !strings.Contains(out, "api  ↓2, skipped: uncommitted changes") {
+		t.Errorf("api not shown as skipped:\n%s", out)
+	}
+	if !strings.Contains(out, "web  ↓1 fast-forward") {
+		t.Errorf("web not shown as pulled:\n%s", out)
+	}
+}